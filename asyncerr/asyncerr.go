@@ -0,0 +1,254 @@
+// Package asyncerr handles errors asynchronously, to keep slow handlers, such
+// as posting errors to a reporting service, out of the goroutine calling
+// errors.Handle().
+//
+// Handler queues errors in a bounded queue, handled by a worker pool. Call
+// FlushOnExit() in initialization code to deliver queued errors before the
+// application exits by redforks/life:
+//
+//  func init() {
+//    h := asyncerr.New(report, asyncerr.Options{Workers: 2})
+//    h.FlushOnExit("errors.report", 10*time.Second)
+//    errors.AddHandler(errors.CausedByFilter(errors.ByBug), h.Handle)
+//  }
+package asyncerr
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redforks/life"
+
+	"github.com/redforks/errors"
+)
+
+// Policy decides what to do if the queue is full.
+type Policy int
+
+const (
+	// Drop the error if the queue is full, counted by Dropped().
+	Drop Policy = iota
+
+	// Block Handle() until the queue has room, or the context passed to
+	// Handle() is done, the error dropped on the later case.
+	Block
+)
+
+// Options of Handler, zero value fields use default values.
+type Options struct {
+	// QueueSize is the capacity of the queue, default to 1024.
+	QueueSize int
+
+	// Workers is number of goroutines calling the handler, default to 1.
+	Workers int
+
+	// Policy on queue full, default to Drop.
+	Policy Policy
+}
+
+type item struct {
+	ctx context.Context
+	err interface{}
+	seq uint64
+}
+
+// flushWaiter waits errors queued before a Flush() call.
+type flushWaiter struct {
+	seq     uint64 // seq of the last error queued before Flush()
+	pending int    // errors of seq <= seq not handled yet
+	done    chan struct{}
+}
+
+// Handler wraps an errors.Handler to handle errors asynchronously.
+type Handler struct {
+	h      errors.Handler
+	policy Policy
+	queue  chan item
+
+	dropped uint64
+
+	mu      sync.Mutex
+	seq     uint64 // seq of the last queued error
+	pending int    // queued and not handled errors
+	waiters []*flushWaiter
+}
+
+// New creates Handler calls h in worker goroutines, workers run until the
+// application exits.
+func New(h errors.Handler, opts Options) *Handler {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	r := &Handler{
+		h:      h,
+		policy: opts.Policy,
+		queue:  make(chan item, opts.QueueSize),
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		go r.work()
+	}
+	return r
+}
+
+// Handle queues err, implements errors.Handler. ctx passed to the handler
+// keeps values of ctx, but never canceled, because the handler normally runs
+// after ctx, such as ctx of a http request, done.
+func (a *Handler) Handle(ctx context.Context, err interface{}) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	it := item{detach(ctx), err, a.begin()}
+
+	// try without blocking first, select picks a random ready case, an error
+	// would be dropped even the queue has room if ctx already done.
+	select {
+	case a.queue <- it:
+		return
+	default:
+	}
+
+	if a.policy == Block {
+		select {
+		case a.queue <- it:
+			return
+		case <-ctx.Done():
+		}
+	}
+
+	atomic.AddUint64(&a.dropped, 1)
+	a.end(it.seq)
+}
+
+// Dropped returns number of errors dropped because the queue is full.
+func (a *Handler) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Flush waits until errors queued before Flush() handled, errors queued
+// after Flush() called not waited, returns ByRuntime error if ctx done before
+// that.
+func (a *Handler) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	if a.pending == 0 {
+		a.mu.Unlock()
+		return nil
+	}
+	w := &flushWaiter{seq: a.seq, pending: a.pending, done: make(chan struct{})}
+	a.waiters = append(a.waiters, w)
+	a.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		a.mu.Lock()
+		a.removeWaiter(w)
+		a.mu.Unlock()
+		return errors.NewRuntime(ctx.Err())
+	}
+}
+
+// FlushOnExit flushes queued errors on shutdown by redforks/life, and before
+// life.Exit(), waits at most timeout. name is the package name registered to
+// life, must be unique. Only call FlushOnExit in initialization code, as
+// life.Register() requires.
+func (a *Handler) FlushOnExit(name string, timeout time.Duration) {
+	flush := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := a.Flush(ctx); err != nil {
+			log.Printf("[asyncerr] %s: flush errors: %s", name, err)
+		}
+	}
+
+	life.Register(name, nil, flush)
+	life.RegisterHook(name, 0, life.OnAbort, flush)
+}
+
+func (a *Handler) work() {
+	for it := range a.queue {
+		a.handle(it)
+	}
+}
+
+func (a *Handler) handle(it item) {
+	defer a.end(it.seq)
+	defer func() {
+		if v := recover(); v != nil {
+			log.Printf("[asyncerr] handler panic: %s", errors.ForLog(v))
+		}
+	}()
+
+	a.h(it.ctx, it.err)
+}
+
+// begin counts a queuing error as pending, returns its seq.
+func (a *Handler) begin() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.seq++
+	a.pending++
+	return a.seq
+}
+
+// end marks the error of seq handled or dropped. Errors pending at a Flush()
+// call all have seq not greater than the waiter's seq, counts them down.
+func (a *Handler) end(seq uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pending--
+	for i := 0; i < len(a.waiters); {
+		w := a.waiters[i]
+		if seq <= w.seq {
+			if w.pending--; w.pending == 0 {
+				close(w.done)
+				a.removeWaiter(w)
+				continue
+			}
+		}
+		i++
+	}
+}
+
+// removeWaiter removes w from waiters, caller must hold a.mu.
+func (a *Handler) removeWaiter(w *flushWaiter) {
+	for i, v := range a.waiters {
+		if v == w {
+			a.waiters = append(a.waiters[:i:i], a.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// detachedContext keeps values of the parent context, but never canceled.
+type detachedContext struct {
+	context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
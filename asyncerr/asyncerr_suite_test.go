@@ -0,0 +1,13 @@
+package asyncerr
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAsyncerr(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Asyncerr Suite")
+}
@@ -0,0 +1,263 @@
+package asyncerr
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redforks/hal"
+	"github.com/redforks/life"
+	"github.com/redforks/testing/reset"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+type ctxKey struct{}
+
+// recorder records handled errors, handler blocks until release closed.
+type recorder struct {
+	lock    sync.Mutex
+	handled []interface{}
+	release chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{release: make(chan struct{})}
+}
+
+func (r *recorder) handle(_ context.Context, err interface{}) {
+	<-r.release
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.handled = append(r.handled, err)
+}
+
+func (r *recorder) getHandled() []interface{} {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]interface{}(nil), r.handled...)
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		rec        *recorder
+		blocking   errors.Handler
+		release    chan struct{}
+		getHandled func() []interface{}
+	)
+
+	BeforeEach(func() {
+		rec = newRecorder()
+		blocking, release, getHandled = rec.handle, rec.release, rec.getHandled
+	})
+
+	It("Handle asynchronously", func() {
+		h := New(blocking, Options{})
+		h.Handle(context.Background(), 1)
+		h.Handle(context.Background(), 2)
+		Ω(getHandled()).Should(BeEmpty())
+
+		close(release)
+		Ω(h.Flush(context.Background())).Should(Succeed())
+		Ω(getHandled()).Should(Equal([]interface{}{1, 2}))
+	})
+
+	It("Context detached", func() {
+		var got context.Context
+		h := New(func(ctx context.Context, _ interface{}) {
+			got = ctx
+		}, Options{})
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, 1))
+		cancel()
+		h.Handle(ctx, 1)
+		Ω(h.Flush(context.Background())).Should(Succeed())
+		Ω(got.Value(ctxKey{})).Should(Equal(1))
+		Ω(got.Err()).Should(BeNil())
+	})
+
+	It("Drop", func() {
+		h := New(blocking, Options{QueueSize: 2})
+		for i := 0; i < 10; i++ {
+			h.Handle(context.Background(), i)
+		}
+		// one taken by the worker, two queued
+		Ω(h.Dropped()).Should(BeNumerically(">=", 7))
+
+		close(release)
+		Ω(h.Flush(context.Background())).Should(Succeed())
+		Ω(uint64(len(getHandled())) + h.Dropped()).Should(Equal(uint64(10)))
+	})
+
+	It("Block", func() {
+		h := New(blocking, Options{QueueSize: 1, Policy: Block})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 5; i++ {
+				h.Handle(context.Background(), i)
+			}
+		}()
+		Consistently(done).ShouldNot(BeClosed())
+
+		close(release)
+		Eventually(done).Should(BeClosed())
+		Ω(h.Flush(context.Background())).Should(Succeed())
+		Ω(getHandled()).Should(Equal([]interface{}{0, 1, 2, 3, 4}))
+		Ω(h.Dropped()).Should(BeZero())
+	})
+
+	It("Block until context done", func() {
+		h := New(blocking, Options{QueueSize: 1, Policy: Block})
+		h.Handle(context.Background(), 0)
+		h.Handle(context.Background(), 1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		h.Handle(ctx, 2)
+		Ω(h.Dropped()).Should(Equal(uint64(1)))
+		close(release)
+	})
+
+	It("Block queues with done context if queue has room", func() {
+		h := New(blocking, Options{QueueSize: 1024, Policy: Block})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for i := 0; i < 1000; i++ {
+			h.Handle(ctx, i)
+		}
+		Ω(h.Dropped()).Should(BeZero())
+
+		close(release)
+		Ω(h.Flush(context.Background())).Should(Succeed())
+		Ω(getHandled()).Should(HaveLen(1000))
+	})
+
+	It("Workers", func() {
+		h := New(blocking, Options{Workers: 3})
+		for i := 0; i < 3; i++ {
+			h.Handle(context.Background(), i)
+		}
+		close(release)
+		Ω(h.Flush(context.Background())).Should(Succeed())
+		Ω(getHandled()).Should(ConsistOf(0, 1, 2))
+	})
+
+	It("Flush timeout", func() {
+		h := New(blocking, Options{})
+		h.Handle(context.Background(), 1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := h.Flush(ctx)
+		Ω(err).Should(HaveOccurred())
+		Ω(errors.GetCausedBy(err)).Should(Equal(errors.ByRuntime))
+		close(release)
+	})
+
+	It("Flush not waits errors queued after it", func() {
+		var (
+			lock    sync.Mutex
+			handled []interface{}
+		)
+		h := New(func(_ context.Context, err interface{}) {
+			time.Sleep(100 * time.Microsecond)
+			lock.Lock()
+			defer lock.Unlock()
+			handled = append(handled, err)
+		}, Options{QueueSize: 16})
+
+		for i := 0; i < 10; i++ {
+			h.Handle(context.Background(), i)
+		}
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					h.Handle(context.Background(), -1)
+				}
+			}
+		}()
+		defer func() {
+			close(stop)
+			<-done
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		Ω(h.Flush(ctx)).Should(Succeed())
+
+		lock.Lock()
+		defer lock.Unlock()
+		Ω(len(handled)).Should(BeNumerically(">=", 10))
+		Ω(handled[:10]).Should(Equal([]interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}))
+	})
+
+	It("Handler panic", func() {
+		n := 0
+		h := New(func(_ context.Context, err interface{}) {
+			if n++; n == 1 {
+				panic("foo")
+			}
+		}, Options{})
+		h.Handle(context.Background(), 1)
+		h.Handle(context.Background(), 2)
+		Ω(h.Flush(context.Background())).Should(Succeed())
+		Ω(n).Should(Equal(2))
+	})
+
+	Context("FlushOnExit", func() {
+		var exitCodes []int
+
+		BeforeEach(func() {
+			reset.Enable()
+			exitCodes = nil
+			hal.Exit = func(n int) {
+				exitCodes = append(exitCodes, n)
+			}
+		})
+
+		AfterEach(func() {
+			reset.Disable()
+			hal.Exit = os.Exit
+		})
+
+		It("Exit", func() {
+			h := New(blocking, Options{})
+			h.FlushOnExit("asyncerr", time.Second)
+			h.Handle(context.Background(), 1)
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				close(release)
+			}()
+			life.Exit(3)
+			Ω(getHandled()).Should(Equal([]interface{}{1}))
+			Ω(exitCodes).Should(Equal([]int{3}))
+		})
+
+		It("Shutdown", func() {
+			h := New(blocking, Options{})
+			h.FlushOnExit("asyncerr", time.Second)
+			life.Start()
+			h.Handle(context.Background(), 1)
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				close(release)
+			}()
+			life.Shutdown()
+			Ω(getHandled()).Should(Equal([]interface{}{1}))
+		})
+	})
+
+})
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Attr is a key/value attribute attached to Error, such as ID of the object
+// failed to process. Put variable values into attributes instead of the error
+// message keeps error message constant, makes grouping errors easier.
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+func (a Attr) String() string {
+	return fmt.Sprintf("%s=%v", a.Key, a.Value)
+}
+
+// With returns a copy of the error with an attribute attached, err itself is
+// not changed, so it is safe to attach attributes to a package level error.
+// The copy is a new occurrence of err, has its own id and creation metadata,
+// errors.Is() reports the copy matches err:
+//
+//  var ErrNotFound = errors.Input("order not found")
+//
+//  return ErrNotFound.With("order", orderID).With("amount", amount)
+func (err *Error) With(key string, value interface{}) *Error {
+	r := err.clone()
+	r.attrs = append(err.attrs[:len(err.attrs):len(err.attrs)], Attr{key, value})
+	return r
+}
+
+// Is reports whether target is the error err copied from, by With() or
+// redaction, makes errors.Is() work on the copy.
+func (err *Error) Is(target error) bool {
+	for e := err.base; e != nil; e = e.base {
+		if e == target {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnAttrs returns attributes attached to this error, not including
+// attributes of inner errors.
+func (err *Error) OwnAttrs() []Attr {
+	return err.attrs
+}
+
+// Attrs returns attributes of the error and all inner errors. If the same key
+// attached multiple times, the outer most one wins.
+func (err *Error) Attrs() []Attr {
+	return GetAttrs(err)
+}
+
+// GetAttrs returns attributes of v along the wrap chain, returns nil if v is
+// not an error or contains no attributes. Use GetAttrs() in Handler to access
+// attributes of the handling error.
+func GetAttrs(v interface{}) []Attr {
+	var (
+		r    []Attr
+		keys = map[string]bool{}
+	)
+
+	e, _ := v.(error)
+	walk(e, func(e error) bool {
+		if er, ok := e.(interface{ OwnAttrs() []Attr }); ok {
+			for _, attr := range er.OwnAttrs() {
+				if !keys[attr.Key] {
+					keys[attr.Key] = true
+					r = append(r, attr)
+				}
+			}
+		}
+		return false
+	})
+	return r
+}
+
+func formatAttrs(attrs []Attr) string {
+	buf := bytes.Buffer{}
+	for i, attr := range attrs {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(attr.String())
+	}
+	return buf.String()
+}
@@ -0,0 +1,62 @@
+package errors_test
+
+import (
+	syserr "errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Attr", func() {
+
+	It("With", func() {
+		e := errors.Input("foo").With("id", 1).With("name", "bar")
+		Ω(e.OwnAttrs()).Should(Equal([]errors.Attr{{"id", 1}, {"name", "bar"}}))
+		Ω(e.Attrs()).Should(Equal(e.OwnAttrs()))
+	})
+
+	It("With not changes the receiver", func() {
+		sentinel := errors.Input("not found").With("id", 1)
+		a, b := sentinel.With("order", 1), sentinel.With("order", 2)
+		Ω(sentinel.OwnAttrs()).Should(Equal([]errors.Attr{{"id", 1}}))
+		Ω(a.OwnAttrs()).Should(Equal([]errors.Attr{{"id", 1}, {"order", 1}}))
+		Ω(b.OwnAttrs()).Should(Equal([]errors.Attr{{"id", 1}, {"order", 2}}))
+		Ω(a.ID()).ShouldNot(Equal(sentinel.ID()))
+		Ω(a.ID()).ShouldNot(Equal(b.ID()))
+	})
+
+	It("Is the error copied from", func() {
+		sentinel := errors.Input("not found")
+		e := sentinel.With("id", 1).With("name", "bar")
+		Ω(syserr.Is(e, sentinel)).Should(BeTrue())
+		Ω(syserr.Is(fmt.Errorf("ctx: %w", e), sentinel)).Should(BeTrue())
+		Ω(syserr.Is(e, errors.Input("not found"))).Should(BeFalse())
+		Ω(syserr.Is(sentinel, e)).Should(BeFalse())
+	})
+
+	It("Accumulate along wrap chain", func() {
+		inner := errors.Input("foo").With("id", 1).With("name", "bar")
+		e := errors.Wrap(errors.ByExternal, inner, "outer").With("id", 2)
+		Ω(e.Attrs()).Should(Equal([]errors.Attr{{"id", 2}, {"name", "bar"}}))
+	})
+
+	It("Through fmt.Errorf wrapper", func() {
+		inner := errors.Input("foo").With("id", 1)
+		Ω(errors.GetAttrs(fmt.Errorf("ctx: %w", inner))).Should(Equal([]errors.Attr{{"id", 1}}))
+	})
+
+	It("Not Error", func() {
+		Ω(errors.GetAttrs(syserr.New("foo"))).Should(BeEmpty())
+		Ω(errors.GetAttrs(1)).Should(BeEmpty())
+		Ω(errors.GetAttrs(nil)).Should(BeEmpty())
+	})
+
+	It("ForLog", func() {
+		e := errors.Input("foo").With("id", 1).With("name", "bar")
+		Ω(errors.ForLog(e)).Should(HavePrefix("foo\nCode: GeneralByInput, CausedBy: ByInput\nID: " + e.ID() + "\nAttributes: id=1 name=bar\n"))
+	})
+
+})
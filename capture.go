@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Capture selects metadata captured on creation of Error, beside the stack.
+type Capture uint8
+
+const (
+	// CaptureTime captures creation time, see Error.Time().
+	CaptureTime Capture = 1 << iota
+
+	// CaptureGoroutine captures id of the goroutine creates the error, see
+	// Error.Goroutine().
+	CaptureGoroutine
+
+	// CaptureLabels captures pprof labels of the context passed to Handle()
+	// to the error passed to handlers, see Error.Labels().
+	CaptureLabels
+)
+
+// capture stores Capture, accessed by sync/atomic.
+var capture = uint32(CaptureTime)
+
+// SetCapture set metadata to capture, default to CaptureTime. Capturing
+// goroutine id parses runtime.Stack(), costs several microseconds, makes
+// creating an error about 10 times slower, only enable CaptureGoroutine if
+// errors are rare.
+func SetCapture(c Capture) {
+	atomic.StoreUint32(&capture, uint32(c))
+}
+
+// Time returns creation time of the error, zero if not captured.
+func (err *Error) Time() time.Time {
+	return err.time
+}
+
+// Goroutine returns id of the goroutine creates the error, 0 if not captured.
+func (err *Error) Goroutine() uint64 {
+	return err.goroutine
+}
+
+// Labels returns pprof labels captured by WithLabels(), or by Handle() to
+// the error passed to handlers if CaptureLabels enabled, nil if not
+// captured.
+func (err *Error) Labels() map[string]string {
+	return err.labels
+}
+
+// WithLabels returns a copy of the error with pprof labels of ctx captured,
+// labels set by pprof.Do() or pprof.WithLabels(). err itself is not
+// changed, same as With().
+func (err *Error) WithLabels(ctx context.Context) *Error {
+	r := err.clone()
+	r.labels = nil
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if r.labels == nil {
+			r.labels = map[string]string{}
+		}
+		r.labels[key] = value
+		return true
+	})
+	return r
+}
+
+// captureMeta sets creation metadata of e selected by SetCapture().
+func captureMeta(e *Error) {
+	c := Capture(atomic.LoadUint32(&capture))
+	if c&CaptureTime != 0 {
+		e.time = time.Now()
+	}
+	if c&CaptureGoroutine != 0 {
+		e.goroutine = goroutineID()
+	}
+}
+
+// ctxLabels returns pprof labels of ctx if CaptureLabels enabled, nil if
+// not enabled or ctx has no labels.
+func ctxLabels(ctx context.Context) map[string]string {
+	if Capture(atomic.LoadUint32(&capture))&CaptureLabels == 0 {
+		return nil
+	}
+
+	var r map[string]string
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if r == nil {
+			r = map[string]string{}
+		}
+		r[key] = value
+		return true
+	})
+	return r
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns id of current goroutine, parsed from the first line of
+// runtime.Stack(): "goroutine 18 [running]:". Returns 0 if failed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], goroutinePrefix)
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// formatMeta formats creation metadata of err for ForLog(), empty if nothing
+// captured.
+func (err *Error) formatMeta() string {
+	var s string
+	switch {
+	case !err.time.IsZero() && err.goroutine != 0:
+		s = "Time: " + err.time.Format(time.RFC3339Nano) + ", Goroutine: " + strconv.FormatUint(err.goroutine, 10) + "\n"
+	case !err.time.IsZero():
+		s = "Time: " + err.time.Format(time.RFC3339Nano) + "\n"
+	case err.goroutine != 0:
+		s = "Goroutine: " + strconv.FormatUint(err.goroutine, 10) + "\n"
+	}
+
+	if len(err.labels) != 0 {
+		keys := make([]string, 0, len(err.labels))
+		for k := range err.labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		s += "Labels:"
+		for _, k := range keys {
+			s += " " + k + "=" + err.labels[k]
+		}
+		s += "\n"
+	}
+	return s
+}
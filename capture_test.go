@@ -0,0 +1,158 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	syserr "errors"
+	"fmt"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redforks/testing/reset"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Capture", func() {
+
+	AfterEach(func() {
+		errors.SetCapture(errors.CaptureTime)
+	})
+
+	It("Default", func() {
+		start := time.Now()
+		e := errors.Bug("foo")
+		Ω(e.Time()).Should(BeTemporally(">=", start))
+		Ω(e.Goroutine()).Should(BeZero())
+		Ω(errors.ForLog(e)).Should(ContainSubstring("\nTime: %s\n", e.Time().Format(time.RFC3339Nano)))
+	})
+
+	It("Time and goroutine", func() {
+		errors.SetCapture(errors.CaptureTime | errors.CaptureGoroutine)
+		start := time.Now()
+		e := errors.Bug("foo")
+		Ω(e.Time()).Should(BeTemporally(">=", start))
+		Ω(e.Time()).Should(BeTemporally("<=", time.Now()))
+		Ω(e.Goroutine()).ShouldNot(BeZero())
+		Ω(e.Labels()).Should(BeNil())
+
+		ch := make(chan *errors.Error)
+		go func() {
+			ch <- errors.Bug("bar")
+		}()
+		Ω((<-ch).Goroutine()).ShouldNot(Equal(e.Goroutine()))
+
+		Ω(errors.ForLog(e)).Should(ContainSubstring("\nTime: %s, Goroutine: %d\n",
+			e.Time().Format(time.RFC3339Nano), e.Goroutine()))
+	})
+
+	It("Disabled", func() {
+		errors.SetCapture(0)
+		e := errors.Bug("foo")
+		Ω(e.Time().IsZero()).Should(BeTrue())
+		Ω(e.Goroutine()).Should(BeZero())
+		Ω(errors.ForLog(e)).ShouldNot(ContainSubstring("Time:"))
+		Ω(errors.ForLog(e)).ShouldNot(ContainSubstring("Goroutine:"))
+
+		errors.SetCapture(errors.CaptureGoroutine)
+		e = errors.Bug("foo")
+		Ω(e.Time().IsZero()).Should(BeTrue())
+		Ω(errors.ForLog(e)).Should(ContainSubstring("\nGoroutine: %d\n", e.Goroutine()))
+	})
+
+	It("WithLabels", func() {
+		ctx := pprof.WithLabels(context.Background(), pprof.Labels("handler", "/order", "tenant", "t1"))
+		orig := errors.Bug("foo")
+		e := orig.WithLabels(ctx)
+		Ω(orig.Labels()).Should(BeNil())
+		Ω(e.Labels()).Should(Equal(map[string]string{"handler": "/order", "tenant": "t1"}))
+		Ω(errors.ForLog(e)).Should(ContainSubstring("\nLabels: handler=/order tenant=t1\n"))
+	})
+
+	Context("Handle", func() {
+		var ctx context.Context
+
+		BeforeEach(func() {
+			reset.Enable()
+			ctx = pprof.WithLabels(context.Background(), pprof.Labels("handler", "/order"))
+		})
+
+		AfterEach(func() {
+			errors.SetHandler(nil)
+			reset.Disable()
+		})
+
+		It("CaptureLabels", func() {
+			var handled []interface{}
+			errors.SetHandler(func(_ context.Context, err interface{}) {
+				handled = append(handled, err)
+			})
+
+			errors.SetCapture(errors.CaptureLabels)
+			e := errors.Bug("foo")
+			outer := errors.Wrap(errors.ByBug, e, "bar")
+			errors.Handle(ctx, outer)
+			Ω(handled[0].(*errors.Error).Labels()).Should(Equal(map[string]string{"handler": "/order"}))
+			Ω(handled[0].(*errors.Error).Inner().(*errors.Error).Labels()).Should(BeNil())
+			Ω(syserr.Is(handled[0].(error), outer)).Should(BeTrue())
+			Ω(outer.Labels()).Should(BeNil())
+
+			// captured labels not overwritten
+			outer = outer.WithLabels(ctx)
+			errors.Handle(pprof.WithLabels(ctx, pprof.Labels("handler", "/user")), outer)
+			Ω(handled[1]).Should(BeIdenticalTo(outer))
+			Ω(outer.Labels()).Should(Equal(map[string]string{"handler": "/order"}))
+
+			// labels captured through wrappers
+			errors.Handle(ctx, fmt.Errorf("ctx: %w", e))
+			var got *errors.Error
+			Ω(syserr.As(handled[2].(error), &got)).Should(BeTrue())
+			Ω(got.Labels()).Should(Equal(map[string]string{"handler": "/order"}))
+			Ω(e.Labels()).Should(BeNil())
+		})
+
+		It("Not enabled", func() {
+			e := errors.Bug("foo")
+			errors.Handle(ctx, e)
+			Ω(e.Labels()).Should(BeNil())
+		})
+	})
+
+	It("JSON", func() {
+		ctx := pprof.WithLabels(context.Background(), pprof.Labels("handler", "/order"))
+		e := errors.Bug("foo").WithLabels(ctx)
+		data, err := json.Marshal(e)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var r errors.Error
+		Ω(json.Unmarshal(data, &r)).Should(Succeed())
+		Ω(r.Time().Equal(e.Time())).Should(BeTrue())
+		Ω(r.Goroutine()).Should(Equal(e.Goroutine()))
+		Ω(r.Labels()).Should(Equal(e.Labels()))
+	})
+
+})
+
+func BenchmarkCapture(b *testing.B) {
+	defer errors.SetCapture(errors.CaptureTime)
+
+	for _, c := range []struct {
+		name    string
+		capture errors.Capture
+	}{
+		{"None", 0},
+		{"Time", errors.CaptureTime},
+		{"TimeGoroutine", errors.CaptureTime | errors.CaptureGoroutine},
+	} {
+		errors.SetCapture(c.capture)
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = errors.Bug("foo")
+			}
+		})
+	}
+}
@@ -43,3 +43,13 @@ const (
 	// error happened
 	NoError
 )
+
+// parseCausedBy parses CausedBy from its name, such as "ByInput".
+func parseCausedBy(s string) (CausedBy, bool) {
+	for c := ByBug; c < NoError; c += ByRuntime - ByBug {
+		if c.String() == s {
+			return c, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,46 @@
+package errors
+
+// unwrap returns inner error of e, returns nil if e not wraps another error.
+func unwrap(e error) error {
+	switch er := e.(type) {
+	case CausedByError:
+		return er.Inner()
+	case interface{ Unwrap() error }:
+		return er.Unwrap()
+	default:
+		return nil
+	}
+}
+
+// walk visits err and errors wrapped by err in pre-order depth-first, the
+// same order as errors.As() of go 1.20, errors wrapped by Unwrap() []error
+// visited in slice order. Stops if fn returns true, returns true if stopped.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range multi.Unwrap() {
+			if walk(e, fn) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(unwrap(err), fn)
+}
+
+// findCausedByError returns the first CausedByError found by walk(), returns
+// nil if not found.
+func findCausedByError(err error) CausedByError {
+	var r CausedByError
+	walk(err, func(e error) bool {
+		r, _ = e.(CausedByError)
+		return r != nil
+	})
+	return r
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Classifier resolves CausedBy of an error, returns false if the error is
+// unknown to the classifier.
+type Classifier func(err error) (CausedBy, bool)
+
+var (
+	// classifiers stores []Classifier, replaced as a whole on change,
+	// Classify() loads it without lock.
+	classifiers atomic.Value
+
+	// classifiersLock serializes changes of classifiers.
+	classifiersLock sync.Mutex
+)
+
+// RegisterClassifier registers a classifier for third-party errors, such as
+// database driver errors. Registered classifiers consulted by Classify() in
+// register order, before built-in rules.
+func RegisterClassifier(c Classifier) {
+	classifiersLock.Lock()
+	defer classifiersLock.Unlock()
+
+	old, _ := classifiers.Load().([]Classifier)
+	classifiers.Store(append(old[:len(old):len(old)], c))
+}
+
+// Classify resolves CausedBy of err, searches the whole wrap chain,
+// including errors wrapped by fmt.Errorf("%w"), and Unwrap() []error of go
+// 1.20 multi errors. Precedence rules:
+//
+//  1. CausedBy of the first CausedByError found in the chain, explicit
+//     CausedBy always takes precedence over classifier rules.
+//  2. The first error in the chain matches a classifier registered by
+//     RegisterClassifier(), or a built-in rule. At each error, registered
+//     classifiers tried in register order, before built-in rules.
+//
+// The chain searched in pre-order depth-first, the same order as
+// errors.As() of go 1.20, outer errors first.
+//
+// Built-in rules:
+//
+//  1. context.Canceled, context.DeadlineExceeded: ByExternal
+//  2. os/io/fs errors, such as *os.PathError, os.ErrNotExist: ByRuntime,
+//     os.ErrClosed, os.ErrInvalid: ByBug
+//  3. net.Error: ByExternal, net.ErrClosed: ByBug
+//  4. syscall.Errno: ByExternal for network errors, such as ECONNREFUSED,
+//     ByBug for EINVAL and EBADF, others ByRuntime
+//  5. io.EOF, io.ErrUnexpectedEOF, json decoding errors, *strconv.NumError:
+//     ByInput, json encoding errors: ByBug
+//
+// Returns NoError if err is nil, ByBug if no rule matches.
+func Classify(err error) CausedBy {
+	if err == nil {
+		return NoError
+	}
+
+	if ce := findCausedByError(err); ce != nil {
+		return ce.Code().Caused()
+	}
+
+	r := ByBug
+	walk(err, func(e error) bool {
+		causedBy, ok := classify(e)
+		if ok {
+			r = causedBy
+		}
+		return ok
+	})
+	return r
+}
+
+// NewClassified wraps an exist error to Error, CausedBy resolved by
+// Classify(). If err is nil, return nil.
+func NewClassified(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	return wrap(err, Classify(err))
+}
+
+func classify(err error) (CausedBy, bool) {
+	cs, _ := classifiers.Load().([]Classifier)
+	for _, c := range cs {
+		if causedBy, ok := c(err); ok {
+			return causedBy, true
+		}
+	}
+
+	switch err {
+	case context.Canceled, context.DeadlineExceeded, os.ErrDeadlineExceeded:
+		return ByExternal, true
+	case os.ErrNotExist, os.ErrExist, os.ErrPermission:
+		return ByRuntime, true
+	case os.ErrClosed, os.ErrInvalid, net.ErrClosed:
+		return ByBug, true
+	case io.EOF, io.ErrUnexpectedEOF:
+		return ByInput, true
+	}
+
+	// syscall.Errno implements net.Error, check it first
+	if causedBy, ok := classifyErrno(err); ok {
+		return causedBy, true
+	}
+
+	switch err.(type) {
+	case *os.PathError, *os.LinkError:
+		return ByRuntime, true
+	case *json.SyntaxError, *json.UnmarshalTypeError, *strconv.NumError:
+		return ByInput, true
+	case *json.UnsupportedTypeError, *json.UnsupportedValueError,
+		*json.InvalidUnmarshalError, *json.MarshalerError:
+		return ByBug, true
+	case net.Error:
+		return ByExternal, true
+	}
+	return 0, false
+}
@@ -0,0 +1,24 @@
+//go:build !plan9
+// +build !plan9
+
+package errors
+
+import "syscall"
+
+func classifyErrno(err error) (CausedBy, bool) {
+	errno, ok := err.(syscall.Errno)
+	if !ok {
+		return 0, false
+	}
+
+	switch errno {
+	case syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED,
+		syscall.ETIMEDOUT, syscall.EHOSTUNREACH, syscall.ENETUNREACH,
+		syscall.ENETDOWN, syscall.EPIPE:
+		return ByExternal, true
+	case syscall.EINVAL, syscall.EBADF:
+		return ByBug, true
+	default:
+		return ByRuntime, true
+	}
+}
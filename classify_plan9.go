@@ -0,0 +1,5 @@
+package errors
+
+func classifyErrno(err error) (CausedBy, bool) {
+	return 0, false
+}
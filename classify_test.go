@@ -0,0 +1,95 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	syserr "errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+type uniqueViolation struct{}
+
+func (uniqueViolation) Error() string {
+	return "unique violation"
+}
+
+var _ = Describe("Classify", func() {
+
+	_, openErr := os.Open("/not/exist/file")
+	_, numErr := strconv.Atoi("foo")
+	var v interface{}
+	jsonErr := json.Unmarshal([]byte("{"), &v)
+	_, marshalErr := json.Marshal(make(chan int))
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	DescribeTable("Classify", func(err error, exp errors.CausedBy) {
+		Ω(errors.Classify(err)).Should(Equal(exp))
+	},
+		Entry("nil", nil, errors.NoError),
+		Entry("plain error", syserr.New("foo"), errors.ByBug),
+		Entry("CausedByError", errors.Input("foo"), errors.ByInput),
+		Entry("context.Canceled", context.Canceled, errors.ByExternal),
+		Entry("context.DeadlineExceeded", context.DeadlineExceeded, errors.ByExternal),
+		Entry("os.PathError", openErr, errors.ByRuntime),
+		Entry("os.ErrNotExist", os.ErrNotExist, errors.ByRuntime),
+		Entry("os.ErrClosed", os.ErrClosed, errors.ByBug),
+		Entry("net.OpError", opErr, errors.ByExternal),
+		Entry("net.ErrClosed", net.ErrClosed, errors.ByBug),
+		Entry("ECONNREFUSED", syscall.ECONNREFUSED, errors.ByExternal),
+		Entry("SyscallError ECONNRESET", os.NewSyscallError("read", syscall.ECONNRESET), errors.ByExternal),
+		Entry("EINVAL", syscall.EINVAL, errors.ByBug),
+		Entry("ENOSPC", syscall.ENOSPC, errors.ByRuntime),
+		Entry("io.EOF", io.EOF, errors.ByInput),
+		Entry("io.ErrUnexpectedEOF", io.ErrUnexpectedEOF, errors.ByInput),
+		Entry("json.SyntaxError", jsonErr, errors.ByInput),
+		Entry("json.UnsupportedTypeError", marshalErr, errors.ByBug),
+		Entry("strconv.NumError", numErr, errors.ByInput),
+		Entry("wrapped by fmt.Errorf", fmt.Errorf("ctx: %w", context.DeadlineExceeded), errors.ByExternal),
+		Entry("outer most wins", fmt.Errorf("ctx: %w", errors.NewInput(syscall.ECONNRESET)), errors.ByInput),
+	)
+
+	Context("RegisterClassifier", func() {
+		errors.RegisterClassifier(func(err error) (errors.CausedBy, bool) {
+			if _, ok := err.(uniqueViolation); ok {
+				return errors.ByInput, true
+			}
+			return 0, false
+		})
+
+		It("Classify", func() {
+			Ω(errors.Classify(uniqueViolation{})).Should(Equal(errors.ByInput))
+			Ω(errors.Classify(fmt.Errorf("insert: %w", uniqueViolation{}))).Should(Equal(errors.ByInput))
+		})
+
+		It("GetCausedBy", func() {
+			Ω(errors.GetCausedBy(uniqueViolation{})).Should(Equal(errors.ByInput))
+			Ω(errors.GetPanicCausedBy(uniqueViolation{})).Should(Equal(errors.ByInput))
+			Ω(errors.GetCode(uniqueViolation{})).Should(Equal(errors.GeneralByInput))
+		})
+	})
+
+	It("GetCausedBy uses built-in rules", func() {
+		Ω(errors.GetCausedBy(syscall.ECONNREFUSED)).Should(Equal(errors.ByExternal))
+		Ω(errors.GetCode(io.EOF)).Should(Equal(errors.GeneralByInput))
+	})
+
+	It("NewClassified", func() {
+		e := errors.NewClassified(syscall.ECONNREFUSED)
+		Ω(e.Code().Caused()).Should(Equal(errors.ByExternal))
+		Ω(e.Err).Should(Equal(syscall.ECONNREFUSED))
+		Ω(e.Stack()).Should(ContainSubstring("classify_test.go"))
+		Ω(errors.NewClassified(nil)).Should(BeNil())
+	})
+
+})
@@ -7,6 +7,7 @@
 package cmdline
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
@@ -63,7 +64,7 @@ func handleError(v interface{}) {
 		buf = buf[0:runtime.Stack(buf, true)]
 		fmt.Fprintln(os.Stderr, string(buf))
 	case errors.ByInput, errors.ByExternal, errors.ByClientBug:
-		fmt.Println(v)
+		fmt.Println(errors.UserMessage(context.Background(), v.(error)))
 	default:
 		panic("Unknown CausedBy")
 	}
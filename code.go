@@ -1,6 +1,7 @@
 package errors
 
-// Code each code is an error, first 8 bit is CausedBy.
+// Code each code is an error, first 8 bit is CausedBy. Use CodeNamespace to
+// allocate codes for a component.
 type Code uint32
 
 // Caused corresponding CausedBy value
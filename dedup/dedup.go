@@ -0,0 +1,235 @@
+// Package dedup suppresses duplicate errors and limits rate of handled
+// errors, to prevent a broken dependency flooding the pager with thousands
+// of identical errors.
+//
+// Errors are grouped by fingerprint. In a window started by the first
+// occurrence of a fingerprint, only the first Options.PerFingerprint errors
+// passed to the wrapped handler, the rest are counted, and reported as a
+// Summary at the end of the window:
+//
+//  d := dedup.New(notifyPager, dedup.Options{Window: 5 * time.Minute, Global: 100})
+//  errors.AddHandler(errors.CausedByFilter(errors.ByExternal), d.Handle)
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redforks/errors"
+)
+
+// Options of Handler, zero value fields use default values.
+type Options struct {
+	// Window of duplicate suppression and rate limits, default to 1 minute.
+	Window time.Duration
+
+	// PerFingerprint is the max number of errors of a fingerprint passed to
+	// the handler in a window, default to 1.
+	PerFingerprint int
+
+	// Global is the max number of errors passed to the handler in a window,
+	// not including summaries, 0 means no limit.
+	Global int
+
+	// Fingerprint groups errors, errors of the same fingerprint are
+	// duplicates, default to errors.Fingerprint().
+	Fingerprint func(err interface{}) string
+}
+
+// Summary reports errors suppressed in a window, passed to the handler at
+// the end of the window. Summary is a CausedByError has code of the
+// suppressed error, to be routed as the suppressed error.
+type Summary struct {
+	// Fingerprint of suppressed errors.
+	Fingerprint string
+
+	// Count of suppressed errors.
+	Count int
+
+	// Err is the first error of the window.
+	Err interface{}
+
+	// Start of the window, and time of the last suppressed error.
+	Start, Last time.Time
+}
+
+var _ errors.CausedByError = &Summary{}
+
+func (s *Summary) Error() string {
+	return fmt.Sprintf("%d more occurrences of: %v", s.Count, s.Err)
+}
+
+// Code returns code of Err.
+func (s *Summary) Code() errors.Code {
+	return errors.GetCode(s.Err)
+}
+
+// Inner returns Err if it is an error.
+func (s *Summary) Inner() error {
+	e, _ := s.Err.(error)
+	return e
+}
+
+// ErrorStack returns Error(), Summary has no stack.
+func (s *Summary) ErrorStack() string {
+	return s.Error()
+}
+
+type entry struct {
+	start, last time.Time
+	passed      int
+	suppressed  int
+	sample      interface{}
+}
+
+// Handler wraps an errors.Handler, suppresses duplicate errors and limits
+// rate of errors.
+type Handler struct {
+	h    errors.Handler
+	opts Options
+	now  func() time.Time
+
+	suppressed uint64
+
+	mu          sync.Mutex
+	entries     map[string]*entry
+	windowStart time.Time // start of global window
+	globalCount int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates Handler calls h, starts a goroutine reports summaries at the
+// end of windows, call Close() to stop it.
+func New(h errors.Handler, opts Options) *Handler {
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	if opts.PerFingerprint <= 0 {
+		opts.PerFingerprint = 1
+	}
+	if opts.Fingerprint == nil {
+		opts.Fingerprint = errors.Fingerprint
+	}
+
+	d := &Handler{
+		h:       h,
+		opts:    opts,
+		now:     time.Now,
+		entries: map[string]*entry{},
+		stop:    make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Handle passes err to the handler if not suppressed, implements
+// errors.Handler.
+func (d *Handler) Handle(ctx context.Context, err interface{}) {
+	fp := d.opts.Fingerprint(err)
+	now := d.now()
+
+	d.mu.Lock()
+	if now.Sub(d.windowStart) >= d.opts.Window {
+		d.windowStart, d.globalCount = now, 0
+	}
+
+	var summary *Summary
+	e := d.entries[fp]
+	if e != nil && now.Sub(e.start) >= d.opts.Window {
+		summary, e = e.summary(fp), nil
+	}
+	if e == nil {
+		e = &entry{start: now, sample: err}
+		d.entries[fp] = e
+	}
+
+	pass := e.passed < d.opts.PerFingerprint && (d.opts.Global <= 0 || d.globalCount < d.opts.Global)
+	if pass {
+		e.passed++
+		d.globalCount++
+	} else {
+		e.suppressed++
+		e.last = now
+		atomic.AddUint64(&d.suppressed, 1)
+	}
+	d.mu.Unlock()
+
+	if summary != nil {
+		d.h(context.Background(), summary)
+	}
+	if pass {
+		d.h(ctx, err)
+	}
+}
+
+// Suppressed returns total number of suppressed errors.
+func (d *Handler) Suppressed() uint64 {
+	return atomic.LoadUint64(&d.suppressed)
+}
+
+// Close stops the summary goroutine, and reports summaries of all windows,
+// including windows not ended.
+func (d *Handler) Close() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		d.sweep(time.Time{})
+	})
+}
+
+func (d *Handler) run() {
+	interval := d.opts.Window / 2
+	if interval <= 0 {
+		interval = d.opts.Window
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.sweep(d.now())
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// sweep removes windows ended before now, and reports their summaries. If
+// now is zero, sweeps all windows.
+func (d *Handler) sweep(now time.Time) {
+	var summaries []*Summary
+
+	d.mu.Lock()
+	for fp, e := range d.entries {
+		if !now.IsZero() && now.Sub(e.start) < d.opts.Window {
+			continue
+		}
+
+		delete(d.entries, fp)
+		if s := e.summary(fp); s != nil {
+			summaries = append(summaries, s)
+		}
+	}
+	d.mu.Unlock()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Start.Before(summaries[j].Start)
+	})
+	for _, s := range summaries {
+		d.h(context.Background(), s)
+	}
+}
+
+// summary returns Summary of the entry, nil if nothing suppressed.
+func (e *entry) summary(fp string) *Summary {
+	if e.suppressed == 0 {
+		return nil
+	}
+	return &Summary{fp, e.suppressed, e.sample, e.start, e.last}
+}
@@ -0,0 +1,149 @@
+package dedup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		lock    sync.Mutex
+		handled []interface{}
+		now     time.Time
+		d       *Handler
+	)
+
+	record := func(_ context.Context, err interface{}) {
+		lock.Lock()
+		defer lock.Unlock()
+		handled = append(handled, err)
+	}
+
+	newHandler := func(opts Options) *Handler {
+		r := New(record, opts)
+		r.now = func() time.Time {
+			return now
+		}
+		return r
+	}
+
+	// newError creates errors of the same fingerprint.
+	newError := func(id int) error {
+		return errors.Externalf("connect to 10.0.0.%d failed", id)
+	}
+
+	BeforeEach(func() {
+		handled = nil
+		now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		d = newHandler(Options{Window: time.Hour})
+	})
+
+	AfterEach(func() {
+		d.Close()
+	})
+
+	It("Suppress duplicates", func() {
+		errs := make([]error, 5)
+		for i := range errs {
+			errs[i] = newError(i)
+			d.Handle(context.Background(), errs[i])
+		}
+		d.Handle(context.Background(), errors.Bug("other"))
+		Ω(handled).Should(HaveLen(2))
+		Ω(handled[0]).Should(Equal(errs[0]))
+		Ω(d.Suppressed()).Should(Equal(uint64(4)))
+
+		now = now.Add(time.Minute)
+		d.Handle(context.Background(), newError(9))
+		Ω(handled).Should(HaveLen(2))
+
+		now = now.Add(time.Hour)
+		d.sweep(now)
+		Ω(handled).Should(HaveLen(3))
+		s := handled[2].(*Summary)
+		Ω(s.Count).Should(Equal(5))
+		Ω(s.Err).Should(Equal(errs[0]))
+		Ω(s.Start).Should(Equal(now.Add(-time.Hour - time.Minute)))
+		Ω(s.Last).Should(Equal(now.Add(-time.Hour)))
+		Ω(s.Error()).Should(Equal("5 more occurrences of: connect to 10.0.0.0 failed"))
+		Ω(errors.GetCode(s)).Should(Equal(errors.GeneralByExternal))
+
+		// new window
+		d.Handle(context.Background(), newError(1))
+		Ω(handled).Should(HaveLen(4))
+	})
+
+	It("Summary reported by the next occurrence after window", func() {
+		d.Handle(context.Background(), newError(1))
+		d.Handle(context.Background(), newError(2))
+
+		now = now.Add(time.Hour)
+		d.Handle(context.Background(), newError(3))
+		Ω(handled).Should(HaveLen(3))
+		Ω(handled[1].(*Summary).Count).Should(Equal(1))
+		Ω(handled[2]).Should(MatchError("connect to 10.0.0.3 failed"))
+	})
+
+	It("No summary if nothing suppressed", func() {
+		d.Handle(context.Background(), newError(1))
+		d.sweep(now.Add(time.Hour))
+		Ω(handled).Should(HaveLen(1))
+		Ω(d.entries).Should(BeEmpty())
+	})
+
+	It("PerFingerprint", func() {
+		d.Close()
+		d = newHandler(Options{Window: time.Hour, PerFingerprint: 3})
+		for i := 0; i < 5; i++ {
+			d.Handle(context.Background(), newError(i))
+		}
+		Ω(handled).Should(HaveLen(3))
+
+		d.Close()
+		Ω(handled).Should(HaveLen(4))
+		Ω(handled[3].(*Summary).Count).Should(Equal(2))
+	})
+
+	It("Global", func() {
+		d.Close()
+		d = newHandler(Options{Window: time.Hour, Global: 2})
+		for i := 0; i < 3; i++ {
+			d.Handle(context.Background(), errors.Bugf("bug %c", 'a'+i))
+		}
+		Ω(handled).Should(HaveLen(2))
+		Ω(d.Suppressed()).Should(Equal(uint64(1)))
+
+		now = now.Add(time.Hour)
+		d.Handle(context.Background(), errors.Bug("bug d"))
+		Ω(handled).Should(HaveLen(3))
+	})
+
+	It("Fingerprint option", func() {
+		d.Close()
+		d = newHandler(Options{Window: time.Hour, Fingerprint: func(interface{}) string {
+			return "same"
+		}})
+		d.Handle(context.Background(), errors.Bug("foo"))
+		d.Handle(context.Background(), errors.Input("bar"))
+		Ω(handled).Should(HaveLen(1))
+	})
+
+	It("Summary goroutine", func() {
+		d.Close()
+		d = New(record, Options{Window: 20 * time.Millisecond})
+		d.Handle(context.Background(), newError(1))
+		d.Handle(context.Background(), newError(2))
+		Eventually(func() int {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(handled)
+		}).Should(Equal(2))
+	})
+
+})
@@ -1,6 +1,12 @@
 package errors
 
-import "bytes"
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
 
 // Error contains error, causedBy, and stack.
 type Error struct {
@@ -8,10 +14,23 @@ type Error struct {
 
 	msg string // overloaded error message
 
-	stack  []uintptr
-	frames []StackFrame
+	stack *stack
 
 	code Code
+
+	attrs []Attr
+	base  *Error // the error copied from, by With() or redaction
+
+	remote      bool
+	remoteStack string
+
+	public string // message safe to show to end-user
+
+	id string
+
+	time      time.Time
+	goroutine uint64
+	labels    map[string]string
 }
 
 var _ CausedByError = &Error{}
@@ -38,18 +57,56 @@ func (err *Error) Unwrap() error {
 	return err.Err
 }
 
+// clone returns a copy of err as a new occurrence, with its own id and
+// creation metadata, errors.Is() reports the copy matches err.
+func (err *Error) clone() *Error {
+	r := *err
+	r.base = err
+	r.id = idGenerator.Load().(IDGenerator)()
+	r.time, r.goroutine = time.Time{}, 0
+	captureMeta(&r)
+	return &r
+}
+
 // StackFrames returns an array of frames containing information about the
 // stack.
 func (err *Error) StackFrames() []StackFrame {
-	if err.frames == nil {
-		err.frames = make([]StackFrame, len(err.stack))
+	if err.stack == nil {
+		return []StackFrame{}
+	}
+	return err.stack.StackFrames()
+}
 
-		for i, pc := range err.stack {
-			err.frames[i] = NewStackFrame(pc)
+// stack is call stack of Error, shared by copies of the Error. Frames
+// resolved on first use, safe to resolve concurrently.
+type stack struct {
+	pcs []uintptr
+
+	once   sync.Once
+	frames []StackFrame
+}
+
+func (s *stack) StackFrames() []StackFrame {
+	s.once.Do(func() {
+		if s.frames != nil {
+			return
+		}
+
+		s.frames = make([]StackFrame, 0, len(s.pcs))
+		if len(s.pcs) == 0 {
+			return
 		}
-	}
 
-	return err.frames
+		frames := runtime.CallersFrames(s.pcs)
+		for {
+			f, more := frames.Next()
+			s.frames = append(s.frames, newStackFrameFromFrame(f))
+			if !more {
+				break
+			}
+		}
+	})
+	return s.frames
 }
 
 // Stack returns the callstack formatted the same way that go does
@@ -80,3 +137,27 @@ func (err *Error) ErrorStack() string {
 	// 	return r + inner.Error()
 	// }
 }
+
+// forLog implements ForLog(), msg is the first line.
+func (err *Error) forLog(msg string) string {
+	s := msg + "\n"
+	s += fmt.Sprintf("Code: %s, CausedBy: %s\n", err.code, err.code.Caused())
+	if err.id != "" {
+		s += "ID: " + err.id + "\n"
+	}
+	if err.public != "" {
+		s += "Public message: " + err.public + "\n"
+	}
+	if len(err.attrs) != 0 {
+		s += "Attributes: " + formatAttrs(err.attrs) + "\n"
+	}
+	s += err.formatMeta()
+	s += err.Stack()
+	if err.remoteStack != "" {
+		s += "Remote stack:\n" + err.remoteStack
+	}
+	if inner := err.Inner(); inner != nil {
+		s += "\nInner error:\n" + ForLog(inner)
+	}
+	return s
+}
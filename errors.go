@@ -66,15 +66,18 @@ func wrap(e error, causedBy CausedBy) *Error {
 		return nil
 	}
 
-	stack := make([]uintptr, maxStackDepth)
-	length := runtime.Callers(3, stack[:])
-	stack = stack[:length]
-	return &Error{
+	pcs := make([]uintptr, maxStackDepth)
+	length := runtime.Callers(3, pcs[:])
+	pcs = pcs[:length]
+	r := &Error{
 		Err:   e,
-		stack: stack,
+		stack: &stack{pcs: pcs},
 
 		code: Code(causedBy),
+		id:   idGenerator.Load().(IDGenerator)(),
 	}
+	captureMeta(r)
+	return r
 }
 
 // NewBug wrap an exist error to ByBug. If e is nil, return nil. If e is
@@ -175,8 +178,34 @@ func NewCaused(causedBy CausedBy, err error) *Error {
 	return wrap(err, causedBy)
 }
 
-// GetCausedBy from any error. If the error is Error interface, call its
-// CausedBy() method. Then all considered as ByBug.
+// Coded create error with specific code, such as a code registered by
+// RegisterCode().
+func Coded(code Code, text string) *Error {
+	e := wrap(syserr.New(text), code.Caused())
+	e.code = code
+	return e
+}
+
+// Codedf sprintf version of Coded
+func Codedf(code Code, text string, a ...interface{}) *Error {
+	e := wrap(fmt.Errorf(text, a...), code.Caused())
+	e.code = code
+	return e
+}
+
+// NewCoded wraps an exist error to Error with specific code, If e is nil,
+// return nil.
+func NewCoded(code Code, err error) *Error {
+	e := wrap(err, code.Caused())
+	if e != nil {
+		e.code = code
+	}
+	return e
+}
+
+// GetCausedBy from any error. Resolved by Classify(), which searches the
+// whole wrap chain, CausedBy of the first CausedByError found takes
+// precedence. Errors unknown to Classify() considered as ByBug.
 //
 // If the error is not a bug, wrap it use NewXXX() function before return:
 //
@@ -191,7 +220,7 @@ func GetCausedBy(e error) CausedBy {
 	case CausedByError:
 		return err.Code().Caused()
 	default:
-		return ByBug
+		return Classify(err)
 	}
 }
 
@@ -202,21 +231,28 @@ func GetPanicCausedBy(v interface{}) CausedBy {
 	switch err := v.(type) {
 	case nil:
 		return NoError
-	case CausedByError:
-		return err.Code().Caused()
+	case error:
+		return GetCausedBy(err)
 	default:
 		return ByBug
 	}
 }
 
-// GetCode returns error code from any value, if v is not CausedByError,
-// returns GenericByBug. Returns NotError if v is nil.
+// GetCode returns error code from any value. For error value, returns code
+// of the first CausedByError found in the wrap chain, same precedence as
+// GetCausedBy(), if not found, returns general code of GetCausedBy(), such as
+// GeneralByBug. Returns NotError if v is nil, GeneralByBug if v is not error.
 func GetCode(v interface{}) Code {
 	switch er := v.(type) {
 	case nil:
 		return NotError
 	case CausedByError:
 		return er.Code()
+	case error:
+		if ce := findCausedByError(er); ce != nil {
+			return ce.Code()
+		}
+		return Code(Classify(er))
 	default:
 		return GeneralByBug
 	}
@@ -224,17 +260,23 @@ func GetCode(v interface{}) Code {
 
 // ForLog convert value to string for better logging:
 //
-//  1. if v is *Error, use .ErrorStack
-//  2. if v is error, use .Error()
-//  3. otherwise, use fmt.Sprint(v)
+//  1. if v is *Error, or embeds *Error, use .ErrorStack, with code,
+//     attributes and inner errors
+//  2. if v is *ValidationError, also its violations
+//  3. if v is *List, ForLog() of each error
+//  4. if v is error, use .Error()
+//  5. otherwise, use fmt.Sprint(v)
 func ForLog(v interface{}) string {
 	switch e := v.(type) {
-	case *Error:
-		s := e.ErrorStack()
-		if inner := e.Inner(); inner != nil {
-			s += "\nInner error:\n" + ForLog(inner)
-		}
-		return s
+	case interface {
+		error
+		forLog(msg string) string
+	}: // *Error, and types embed *Error, such as grpcerr.StatusError
+		return e.forLog(e.Error())
+	case *ValidationError:
+		return e.forLog()
+	case *List:
+		return e.forLog()
 	case error:
 		return e.Error()
 	default:
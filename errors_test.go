@@ -2,6 +2,8 @@ package errors_test
 
 import (
 	syserr "errors"
+	"fmt"
+	"io"
 	"strings"
 
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -89,6 +91,33 @@ var _ = Describe("errors", func() {
 
 		})
 
+		It("Wrapped by fmt.Errorf", func() {
+			e := fmt.Errorf("ctx: %w", errors.External("foo"))
+			Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByExternal))
+			Ω(errors.GetPanicCausedBy(e)).Should(Equal(errors.ByExternal))
+			Ω(errors.GetCode(e)).Should(Equal(errors.GeneralByExternal))
+		})
+
+		It("Multi unwrap", func() {
+			e := syserr.Join(syserr.New("foo"), fmt.Errorf("ctx: %w", errors.Input("bar")))
+			Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByInput))
+			Ω(errors.GetCode(e)).Should(Equal(errors.GeneralByInput))
+		})
+
+		It("Outer most wins", func() {
+			e := fmt.Errorf("ctx: %w", errors.NewInput(errors.External("foo")))
+			Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByInput))
+
+			e = syserr.Join(errors.Runtime("foo"), errors.Input("bar"))
+			Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByRuntime))
+		})
+
+		It("CausedByError takes precedence over classifier", func() {
+			e := fmt.Errorf("%w: %w", io.EOF, errors.External("foo"))
+			Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByExternal))
+			Ω(errors.GetCode(e)).Should(Equal(errors.GeneralByExternal))
+		})
+
 	})
 
 	Context("GetPanicCausedBy", func() {
@@ -135,6 +164,29 @@ var _ = Describe("errors", func() {
 		Entry("ByClientBug", errors.ByClientBug),
 	)
 
+	Context("Coded", func() {
+		code := errors.NewCode(errors.ByInput, 0x17)
+
+		It("Coded", func() {
+			e := errors.Coded(code, "foo")
+			Ω(e.Code()).Should(Equal(code))
+			Ω(e.Error()).Should(Equal("foo"))
+		})
+
+		It("Codedf", func() {
+			e := errors.Codedf(code, "foo %d", 3)
+			Ω(e.Code()).Should(Equal(code))
+			Ω(e.Error()).Should(Equal("foo 3"))
+		})
+
+		It("NewCoded", func() {
+			e := errors.NewCoded(code, syserr.New("foo"))
+			Ω(e.Code()).Should(Equal(code))
+			Ω(e.Error()).Should(Equal("foo"))
+			Ω(errors.NewCoded(code, nil)).Should(BeNil())
+		})
+	})
+
 	DescribeTable("stacktrace", func(e *errors.Error) {
 		stack := e.Stack()
 		Ω(stack).Should(ContainSubstring("errors_test.go"))
@@ -157,6 +209,9 @@ var _ = Describe("errors", func() {
 		Entry("Caused", errors.Caused(errors.ByInput, "foo")),
 		Entry("Causedf", errors.Causedf(errors.ByInput, "foo %d", 1)),
 		Entry("NewCaused", errors.NewCaused(errors.ByInput, syserr.New("foo"))),
+		Entry("Coded", errors.Coded(errors.GeneralByInput, "foo")),
+		Entry("Codedf", errors.Codedf(errors.GeneralByInput, "foo %d", 1)),
+		Entry("NewCoded", errors.NewCoded(errors.GeneralByInput, syserr.New("foo"))),
 		Entry("Wrap", errors.Wrap(errors.ByInput, syserr.New("foo"), "bla")),
 		Entry("Wrapf", errors.Wrapf(errors.ByInput, syserr.New("foo"), "bla %d", 1)),
 	)
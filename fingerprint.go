@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const thisPackage = "github.com/redforks/errors"
+
+var (
+	uuidRe   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexIDRe  = regexp.MustCompile(`\b(?:0[xX])?[0-9a-fA-F]{8,}\b`)
+	ulidRe   = regexp.MustCompile(`\b[0-9A-HJKMNP-TV-Z]{26}\b`)
+	numberRe = regexp.MustCompile(`\d+(?:\.\d+)?`)
+)
+
+// Fingerprint returns fingerprint of the error, see Fingerprint().
+func (err *Error) Fingerprint() string {
+	return Fingerprint(err)
+}
+
+// Fingerprint returns a stable fingerprint of v to group errors of the same
+// kind, such as for deduplication, metrics and reporting. Fingerprint not
+// changed by line number shifting, it is hash of:
+//
+//  1. Code and CausedBy, see GetCode().
+//  2. Function names of StackFrames() of the first error in the wrap chain
+//     has stack, frames of runtime and this package ignored.
+//  3. Message with uuids, hex ids, ULIDs and numbers normalized, such as
+//     "order 42 not found" and "order 43 not found" are the same.
+//
+// Returns "" if v is nil. v can be non-error value, such as a panic value.
+func Fingerprint(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	h := sha256.New()
+	code := GetCode(v)
+	fmt.Fprintf(h, "%08x %s\n", uint32(code), code.Caused())
+
+	if err, ok := v.(error); ok {
+		walk(err, func(e error) bool {
+			se, ok := e.(interface{ StackFrames() []StackFrame })
+			if !ok {
+				return false
+			}
+
+			for _, frame := range se.StackFrames() {
+				if frame.Package == thisPackage || frame.Package == "runtime" ||
+					strings.HasPrefix(frame.Package, "runtime/") {
+					continue
+				}
+				fmt.Fprintf(h, "%s.%s\n", frame.Package, frame.Name)
+			}
+			return true
+		})
+	}
+
+	fmt.Fprint(h, normalizeMessage(fmt.Sprint(v)))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// normalizeMessage replaces variable parts of s, such as ids and numbers.
+func normalizeMessage(s string) string {
+	s = uuidRe.ReplaceAllString(s, "<uuid>")
+	s = hexIDRe.ReplaceAllStringFunc(s, func(m string) string {
+		if strings.ContainsAny(m, "0123456789") {
+			return "<id>"
+		}
+		return m
+	})
+	s = ulidRe.ReplaceAllStringFunc(s, func(m string) string {
+		if strings.ContainsAny(m, "0123456789") {
+			return "<id>"
+		}
+		return m
+	})
+	return numberRe.ReplaceAllString(s, "<n>")
+}
@@ -0,0 +1,63 @@
+package errors_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Fingerprint", func() {
+
+	It("Line number ignored", func() {
+		a := errors.Input("foo")
+		b := errors.Input("foo")
+		Ω(a.Fingerprint()).Should(HaveLen(32))
+		Ω(a.Fingerprint()).Should(Equal(b.Fingerprint()))
+		Ω(errors.Fingerprint(a)).Should(Equal(a.Fingerprint()))
+	})
+
+	It("Function", func() {
+		a := func() *errors.Error { return errors.Input("foo") }
+		b := func() *errors.Error { return errors.Input("foo") }
+		Ω(a().Fingerprint()).Should(Equal(a().Fingerprint()))
+		Ω(a().Fingerprint()).ShouldNot(Equal(b().Fingerprint()))
+	})
+
+	It("Code", func() {
+		Ω(errors.Input("foo").Fingerprint()).ShouldNot(Equal(errors.External("foo").Fingerprint()))
+
+		code := errors.NewCode(errors.ByInput, 0x2501)
+		Ω(errors.Coded(code, "foo").Fingerprint()).ShouldNot(Equal(errors.Input("foo").Fingerprint()))
+	})
+
+	It("Stack of wrapped error", func() {
+		inner := func() error { return errors.Input("foo") }
+		wrap := func(err error) error { return errors.NewExternal(err) }
+		Ω(errors.Fingerprint(wrap(inner()))).Should(Equal(errors.Fingerprint(wrap(inner()))))
+	})
+
+	DescribeTable("Message normalized", func(a, b string, same bool) {
+		fa, fb := errors.Input(a).Fingerprint(), errors.Input(b).Fingerprint()
+		if same {
+			Ω(fa).Should(Equal(fb))
+		} else {
+			Ω(fa).ShouldNot(Equal(fb))
+		}
+	},
+		Entry("numbers", "order 42 not found", "order 1234 not found", true),
+		Entry("decimal", "amount 1.5 exceeds", "amount 20.25 exceeds", true),
+		Entry("uuid", "user 123e4567-e89b-12d3-a456-426614174000 locked", "user 00000000-0000-0000-0000-000000000001 locked", true),
+		Entry("hex id", "object 5f2b9c1e0a7d not found", "object 0x1a2b3c4d5e not found", true),
+		Entry("ulid", "error 01ARZ3NDEKTSV4RRFFQ69G5FAV", "error 01BX5ZZKBKACTAV9WEVGEMMVRZ", true),
+		Entry("different text", "order not found", "user not found", false),
+	)
+
+	It("Not error", func() {
+		Ω(errors.Fingerprint(nil)).Should(BeEmpty())
+		Ω(errors.Fingerprint("panic 1")).Should(Equal(errors.Fingerprint("panic 2")))
+		Ω(errors.Fingerprint("panic 1")).ShouldNot(Equal(errors.Fingerprint("oops")))
+	})
+
+})
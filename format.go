@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+var _ fmt.Formatter = &Error{}
+
+// Format implements fmt.Formatter:
+//
+//  %s, %v  error message, same as .Error()
+//  %q      quoted error message
+//  %+v     message, code, CausedBy, attributes, stack and inner errors, same
+//          as ForLog()
+//  %#v     go syntax representation, without stack
+func (err *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			_, _ = io.WriteString(s, ForLog(err))
+		case s.Flag('#'):
+			_, _ = fmt.Fprintf(s, "&errors.Error{Err:%#v, msg:%q, code:0x%08x, attrs:%#v}",
+				err.Err, err.msg, uint32(err.code), err.attrs)
+		default:
+			_, _ = io.WriteString(s, err.Error())
+		}
+	case 's':
+		_, _ = io.WriteString(s, err.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", err.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*errors.Error=%s)", verb, err.Error())
+	}
+}
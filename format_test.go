@@ -0,0 +1,45 @@
+package errors_test
+
+import (
+	syserr "errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Format", func() {
+	var e *errors.Error
+
+	BeforeEach(func() {
+		e = errors.Wrap(errors.ByInput, errors.Bug("foo"), "bar").With("id", 1)
+	})
+
+	It("%s %v %q", func() {
+		Ω(fmt.Sprintf("%s", e)).Should(Equal("bar"))
+		Ω(fmt.Sprintf("%v", e)).Should(Equal("bar"))
+		Ω(fmt.Sprintf("%q", e)).Should(Equal(`"bar"`))
+	})
+
+	It("%+v", func() {
+		s := fmt.Sprintf("%+v", e)
+		Ω(s).Should(Equal(errors.ForLog(e)))
+		Ω(s).Should(HavePrefix("bar\nCode: GeneralByInput, CausedBy: ByInput\nID: " + e.ID() + "\nAttributes: id=1\n"))
+		Ω(s).Should(ContainSubstring("format_test.go"))
+		Ω(s).Should(ContainSubstring("Inner error:\nfoo\nCode: GeneralByBug, CausedBy: ByBug\n"))
+	})
+
+	It("%#v", func() {
+		e = errors.Input("foo").With("id", 1)
+		e.Err = syserr.New("foo")
+		Ω(fmt.Sprintf("%#v", e)).Should(Equal(
+			`&errors.Error{Err:&errors.errorString{s:"foo"}, msg:"", code:0x04000000, attrs:[]errors.Attr{errors.Attr{Key:"id", Value:1}}}`))
+	})
+
+	It("Bad verb", func() {
+		Ω(fmt.Sprintf("%d", e)).Should(Equal("%!d(*errors.Error=bar)"))
+	})
+
+})
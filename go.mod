@@ -1,6 +1,6 @@
 module github.com/redforks/errors
 
-go 1.12
+go 1.20
 
 require (
 	github.com/onsi/ginkgo v1.10.3
@@ -8,4 +8,19 @@ require (
 	github.com/redforks/hal v1.0.0
 	github.com/redforks/life v1.0.0
 	github.com/redforks/testing v1.0.0
+	google.golang.org/genproto v0.0.0-20200806141610-86f49bd18e98
+	google.golang.org/grpc v1.27.1
+)
+
+require (
+	github.com/golang/protobuf v1.4.1 // indirect
+	github.com/hpcloud/tail v1.0.0 // indirect
+	github.com/stevenle/topsort v0.0.0-20130922064739-8130c1d7596b // indirect
+	golang.org/x/net v0.0.0-20190311183353-d8887717615a // indirect
+	golang.org/x/sys v0.0.0-20190215142949-d0b11bdaac8a // indirect
+	golang.org/x/text v0.3.0 // indirect
+	google.golang.org/protobuf v1.24.0 // indirect
+	gopkg.in/fsnotify.v1 v1.4.7 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v2 v2.2.4 // indirect
 )
@@ -0,0 +1,287 @@
+// Package grpcerr converts errors between CausedBy errors and gRPC status.
+//
+// ToStatus() maps CausedBy to gRPC code, and carries error code, redacted
+// attributes of client errors and optionally stack in status details,
+// FromStatus() converts it back.
+// Server interceptors recover panics, handle errors by errors.Handle(), and
+// returns status to client. Client interceptors converts status returned by
+// server to Error.
+package grpcerr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/redforks/errors"
+)
+
+// Domain of errdetails.ErrorInfo status detail created by ToStatus().
+const Domain = "github.com/redforks/errors"
+
+const attrPrefix = "attr."
+
+var (
+	grpcCodesLock sync.RWMutex
+	grpcCodes     = map[errors.Code]codes.Code{}
+
+	// sendStack stores bool, false if not set.
+	sendStack atomic.Value
+)
+
+// SetGRPCCode overrides gRPC code of an error code. Safe to call
+// concurrently with request handlers.
+func SetGRPCCode(code errors.Code, grpcCode codes.Code) {
+	grpcCodesLock.Lock()
+	defer grpcCodesLock.Unlock()
+
+	grpcCodes[code] = grpcCode
+}
+
+// SetSendStack set whether ToStatus() includes stack, error message, and
+// attributes of errors not caused by the client in status details, default
+// is false. Only enable it if clients are trusted.
+func SetSendStack(b bool) {
+	sendStack.Store(b)
+}
+
+// GRPCCode returns gRPC code of v:
+//
+//  ByInput, ByClientBug: InvalidArgument
+//  ByExternal:           Unavailable
+//  ByBug, ByRuntime:     Internal
+//
+// Code set by SetGRPCCode() takes precedence. Returns OK if v is nil.
+func GRPCCode(v interface{}) codes.Code {
+	code := errors.GetCode(v)
+	grpcCodesLock.RLock()
+	c, ok := grpcCodes[code]
+	grpcCodesLock.RUnlock()
+	if ok {
+		return c
+	}
+
+	switch code.Caused() {
+	case errors.CausedBy(0):
+		return codes.OK
+	case errors.ByInput, errors.ByClientBug:
+		return codes.InvalidArgument
+	case errors.ByExternal:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// ToStatus converts err to gRPC status, returns nil if err is nil. If err
+// already is a gRPC status error, returns its status, but StatusError
+// returned by a gRPC call is converted as other errors.
+//
+// Status message is errors.PublicMessage() of err, error code and id are
+// carried by errdetails.ErrorInfo detail, and error message and stack by
+// errdetails.DebugInfo if SetSendStack(true). Attributes carried by
+// ErrorInfo if err is ByInput or ByClientBug, or SetSendStack(true), after
+// redaction by errors.Redact(), other errors hide internal details.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if st, ok := statusOf(err); ok {
+		return st
+	}
+
+	code := errors.GetCode(err)
+	info := &errdetails.ErrorInfo{
+		Reason:   fmt.Sprintf("%08X", uint32(code)),
+		Domain:   Domain,
+		Metadata: map[string]string{"code": strconv.FormatUint(uint64(code), 16)},
+	}
+	if id := errors.ErrorID(err); id != "" {
+		info.Metadata["id"] = id
+	}
+	send, _ := sendStack.Load().(bool)
+	redacted := errors.Redact(err).(error)
+	switch code.Caused() {
+	case errors.ByInput, errors.ByClientBug:
+		send = true
+	}
+	if send {
+		for _, attr := range errors.GetAttrs(redacted) {
+			info.Metadata[attrPrefix+attr.Key] = fmt.Sprint(attr.Value)
+		}
+	}
+
+	st := status.New(GRPCCode(err), errors.PublicMessage(err))
+	r, e := st.WithDetails(info)
+	if e != nil {
+		return st
+	}
+
+	if b, _ := sendStack.Load().(bool); b {
+		debug := &errdetails.DebugInfo{Detail: redacted.Error()}
+		if er, ok := err.(*errors.Error); ok {
+			for _, frame := range er.StackFrames() {
+				debug.StackEntries = append(debug.StackEntries, strings.TrimSuffix(frame.String(), "\n"))
+			}
+		}
+		if withDebug, e := r.WithDetails(debug); e == nil {
+			r = withDebug
+		}
+	}
+	return r
+}
+
+// FromStatus converts gRPC status to Error, returns nil if st is nil or OK.
+// Error code, id, attributes and stack restored if st created by ToStatus(),
+// otherwise code resolved by gRPC code:
+//
+//  InvalidArgument, OutOfRange, FailedPrecondition, AlreadyExists, NotFound,
+//  PermissionDenied, Unauthenticated: ByInput
+//  Unimplemented: ByBug
+//  Others: ByExternal
+//
+// Status message restored as public message, and error message if status
+// contains error message sent by SetSendStack(true). Attribute values
+// restored as strings.
+func FromStatus(st *status.Status) *errors.Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	code, found := errors.NotError, false
+	var (
+		attrs []errors.Attr
+		stack []string
+		id    string
+		msg   = st.Message()
+	)
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.Domain != Domain {
+				continue
+			}
+			if c, err := strconv.ParseUint(d.Metadata["code"], 16, 32); err == nil {
+				code, found = errors.Code(c), true
+			}
+			attrs, id = metadataAttrs(d.Metadata), d.Metadata["id"]
+		case *errdetails.DebugInfo:
+			stack = d.StackEntries
+			if d.Detail != "" {
+				msg = d.Detail
+			}
+		}
+	}
+	if !found {
+		code = codeOf(st.Code())
+	}
+
+	e := errors.NewRemote(code, msg, joinStack(stack)).WithPublicMessage(st.Message())
+	for _, attr := range attrs {
+		e = e.With(attr.Key, attr.Value)
+	}
+	if id != "" {
+		e = e.WithID(id)
+	}
+	return e
+}
+
+// FromError converts error returned by gRPC call to StatusError, returns err
+// as is if it is not a gRPC status error.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if e := FromStatus(st); e != nil {
+		return &StatusError{e, st}
+	}
+	return nil
+}
+
+// errorAlias embeds *errors.Error in StatusError, a field named Error hides
+// the Error() method.
+type errorAlias = errors.Error
+
+// StatusError is Error converted from gRPC status error by FromError(),
+// GRPCStatus() returns the original status, so status.Code() and
+// status.FromError() work the same as on the original error.
+type StatusError struct {
+	*errorAlias
+
+	status *status.Status
+}
+
+// GRPCStatus returns the original status.
+func (e *StatusError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// Inner returns the converted Error, implements CausedByError interface.
+func (e *StatusError) Inner() error {
+	return e.errorAlias
+}
+
+// Unwrap is alias of Inner method, errors.As() finds the converted Error.
+func (e *StatusError) Unwrap() error {
+	return e.errorAlias
+}
+
+// statusOf returns status of gRPC status error created by the server, false
+// if err is not a status error, or converted by FromError().
+func statusOf(err interface{}) (*status.Status, bool) {
+	if _, ok := err.(*StatusError); ok {
+		return nil, false
+	}
+	if st, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return st.GRPCStatus(), true
+	}
+	return nil, false
+}
+
+func codeOf(c codes.Code) errors.Code {
+	switch c {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition,
+		codes.AlreadyExists, codes.NotFound, codes.PermissionDenied,
+		codes.Unauthenticated:
+		return errors.GeneralByInput
+	case codes.Unimplemented:
+		return errors.GeneralByBug
+	default:
+		return errors.GeneralByExternal
+	}
+}
+
+func metadataAttrs(md map[string]string) []errors.Attr {
+	var keys []string
+	for k := range md {
+		if strings.HasPrefix(k, attrPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	r := make([]errors.Attr, 0, len(keys))
+	for _, k := range keys {
+		r = append(r, errors.Attr{Key: strings.TrimPrefix(k, attrPrefix), Value: md[k]})
+	}
+	return r
+}
+
+func joinStack(entries []string) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	return strings.Join(entries, "\n") + "\n"
+}
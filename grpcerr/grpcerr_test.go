@@ -0,0 +1,132 @@
+package grpcerr
+
+import (
+	syserr "errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("grpcerr", func() {
+
+	DescribeTable("GRPCCode", func(v interface{}, exp codes.Code) {
+		Ω(GRPCCode(v)).Should(Equal(exp))
+	},
+		Entry("nil", nil, codes.OK),
+		Entry("ByInput", errors.Input("foo"), codes.InvalidArgument),
+		Entry("ByClientBug", errors.ClientBug("foo"), codes.InvalidArgument),
+		Entry("ByExternal", errors.External("foo"), codes.Unavailable),
+		Entry("ByBug", errors.Bug("foo"), codes.Internal),
+		Entry("ByRuntime", errors.Runtime("foo"), codes.Internal),
+		Entry("plain error", syserr.New("foo"), codes.Internal),
+	)
+
+	It("SetGRPCCode", func() {
+		code := errors.NewCode(errors.ByInput, 0xff0201)
+		SetGRPCCode(code, codes.NotFound)
+		defer func() {
+			grpcCodesLock.Lock()
+			defer grpcCodesLock.Unlock()
+			delete(grpcCodes, code)
+		}()
+
+		Ω(GRPCCode(errors.Coded(code, "foo"))).Should(Equal(codes.NotFound))
+	})
+
+	It("Round trip", func() {
+		code := errors.NewCode(errors.ByInput, 0xff0202)
+		orig := errors.Coded(code, "foo").With("id", 1).With("name", "bar")
+		st := ToStatus(orig)
+		Ω(st.Code()).Should(Equal(codes.InvalidArgument))
+		Ω(st.Message()).Should(Equal("foo"))
+
+		e := FromStatus(st)
+		Ω(e.Error()).Should(Equal("foo"))
+		Ω(e.Code()).Should(Equal(code))
+		Ω(e.Remote()).Should(BeTrue())
+		Ω(e.RemoteStack()).Should(BeEmpty())
+		Ω(e.ID()).Should(Equal(orig.ID()))
+		Ω(e.Attrs()).Should(Equal([]errors.Attr{{Key: "id", Value: "1"}, {Key: "name", Value: "bar"}}))
+	})
+
+	It("Public message", func() {
+		st := ToStatus(errors.Bug("secret"))
+		Ω(st.Message()).Should(Equal(errors.Apology))
+
+		e := FromStatus(st)
+		Ω(e.Error()).Should(Equal(errors.Apology))
+		Ω(e.PublicMessage()).Should(Equal(errors.Apology))
+
+		st = ToStatus(errors.External("secret").WithPublicMessage("foo"))
+		Ω(st.Message()).Should(Equal("foo"))
+	})
+
+	It("Send stack", func() {
+		SetSendStack(true)
+		defer SetSendStack(false)
+
+		st := ToStatus(errors.Bug("secret"))
+		Ω(st.Message()).Should(Equal(errors.Apology))
+
+		e := FromStatus(st)
+		Ω(e.RemoteStack()).Should(ContainSubstring("grpcerr_test.go"))
+		Ω(e.Error()).Should(Equal("secret"))
+		Ω(e.PublicMessage()).Should(Equal(errors.Apology))
+	})
+
+	It("Attributes of internal errors not sent", func() {
+		orig := errors.Bug("db failed").With("password", "hunter2").With("dsn", "postgres://u:p@h/db")
+		e := FromStatus(ToStatus(orig))
+		Ω(e.Attrs()).Should(BeEmpty())
+		Ω(e.ID()).Should(Equal(orig.ID()))
+
+		SetSendStack(true)
+		defer SetSendStack(false)
+		e = FromStatus(ToStatus(orig))
+		Ω(e.Attrs()).Should(Equal([]errors.Attr{{Key: "dsn", Value: "postgres://u:p@h/db"}, {Key: "password", Value: "***"}}))
+	})
+
+	It("Attributes redacted", func() {
+		e := FromStatus(ToStatus(errors.Input("login failed").With("password", "hunter2").With("user", "bob")))
+		Ω(e.Attrs()).Should(Equal([]errors.Attr{{Key: "password", Value: "***"}, {Key: "user", Value: "bob"}}))
+	})
+
+	It("Status error", func() {
+		err := status.Error(codes.NotFound, "foo")
+		Ω(ToStatus(err).Code()).Should(Equal(codes.NotFound))
+	})
+
+	It("nil", func() {
+		Ω(ToStatus(nil)).Should(BeNil())
+		Ω(FromStatus(nil)).Should(BeNil())
+		Ω(FromStatus(status.New(codes.OK, ""))).Should(BeNil())
+		Ω(FromError(nil)).Should(BeNil())
+	})
+
+	DescribeTable("FromStatus without details", func(c codes.Code, exp errors.CausedBy) {
+		e := FromStatus(status.New(c, "foo"))
+		Ω(e.Error()).Should(Equal("foo"))
+		Ω(errors.GetCausedBy(e)).Should(Equal(exp))
+	},
+		Entry("InvalidArgument", codes.InvalidArgument, errors.ByInput),
+		Entry("NotFound", codes.NotFound, errors.ByInput),
+		Entry("Unimplemented", codes.Unimplemented, errors.ByBug),
+		Entry("Unavailable", codes.Unavailable, errors.ByExternal),
+		Entry("Internal", codes.Internal, errors.ByExternal),
+	)
+
+	It("FromError", func() {
+		plain := syserr.New("foo")
+		Ω(FromError(plain)).Should(BeIdenticalTo(plain))
+
+		err := FromError(status.Error(codes.InvalidArgument, "foo"))
+		Ω(errors.GetCausedBy(err)).Should(Equal(errors.ByInput))
+	})
+
+})
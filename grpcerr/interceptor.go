@@ -0,0 +1,96 @@
+package grpcerr
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+
+	"github.com/redforks/errors"
+)
+
+// UnaryServerInterceptor recovers panics of the handler, handles panics and
+// returned errors by errors.Handle(), and converts them to status by
+// ToStatus(). Errors already are gRPC status errors returned as is without
+// handling, they are deliberately responses, except StatusError returned by
+// calls to other services.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			resp, err = nil, handleError(ctx, v)
+		}
+	}()
+
+	resp, err = handler(ctx, req)
+	if err != nil {
+		err = handleError(ctx, err)
+	}
+	return
+}
+
+// StreamServerInterceptor is stream version of UnaryServerInterceptor.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = handleError(ss.Context(), v)
+		}
+	}()
+
+	if err = handler(srv, ss); err != nil {
+		err = handleError(ss.Context(), err)
+	}
+	return
+}
+
+// UnaryClientInterceptor converts status error returned by server to
+// StatusError by FromError().
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return FromError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// StreamClientInterceptor converts status errors returned by stream to
+// StatusError by FromError().
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, FromError(err)
+	}
+	return clientStream{s}, nil
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s clientStream) SendMsg(m interface{}) error {
+	return fromStreamError(s.ClientStream.SendMsg(m))
+}
+
+func (s clientStream) RecvMsg(m interface{}) error {
+	return fromStreamError(s.ClientStream.RecvMsg(m))
+}
+
+func (s clientStream) CloseSend() error {
+	return fromStreamError(s.ClientStream.CloseSend())
+}
+
+func fromStreamError(err error) error {
+	if err == io.EOF {
+		return err
+	}
+	return FromError(err)
+}
+
+func handleError(ctx context.Context, v interface{}) error {
+	if st, ok := statusOf(v); ok {
+		return st.Err()
+	}
+
+	errors.Handle(ctx, v)
+
+	err, ok := v.(error)
+	if !ok {
+		err = errors.Bugf("%v", v)
+	}
+	return ToStatus(err).Err()
+}
@@ -0,0 +1,157 @@
+package grpcerr
+
+import (
+	"context"
+	syserr "errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/redforks/testing/reset"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+type serverStream struct {
+	grpc.ServerStream
+}
+
+func (serverStream) Context() context.Context {
+	return context.Background()
+}
+
+type fakeClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s fakeClientStream) RecvMsg(interface{}) error {
+	return s.err
+}
+
+var _ = Describe("Interceptor", func() {
+	var handled []interface{}
+
+	BeforeEach(func() {
+		reset.Enable()
+		handled = nil
+		errors.SetHandler(func(_ context.Context, err interface{}) {
+			handled = append(handled, err)
+		})
+	})
+
+	AfterEach(func() {
+		errors.SetHandler(nil)
+		reset.Disable()
+	})
+
+	unary := func(handler grpc.UnaryHandler) (interface{}, error) {
+		return UnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	}
+
+	It("Unary no error", func() {
+		resp, err := unary(func(context.Context, interface{}) (interface{}, error) {
+			return 1, nil
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp).Should(Equal(1))
+		Ω(handled).Should(BeEmpty())
+	})
+
+	It("Unary returns error", func() {
+		e := errors.Input("foo")
+		_, err := unary(func(context.Context, interface{}) (interface{}, error) {
+			return nil, e
+		})
+		Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		Ω(handled).Should(Equal([]interface{}{e}))
+	})
+
+	It("Unary panic", func() {
+		_, err := unary(func(context.Context, interface{}) (interface{}, error) {
+			panic(3)
+		})
+		Ω(status.Code(err)).Should(Equal(codes.Internal))
+		Ω(handled).Should(Equal([]interface{}{3}))
+	})
+
+	It("Unary status error", func() {
+		_, err := unary(func(context.Context, interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "foo")
+		})
+		Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		Ω(handled).Should(BeEmpty())
+	})
+
+	It("Stream panic", func() {
+		err := StreamServerInterceptor(nil, serverStream{}, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+			panic(errors.External("foo"))
+		})
+		Ω(status.Code(err)).Should(Equal(codes.Unavailable))
+		Ω(handled).Should(HaveLen(1))
+	})
+
+	It("Stream returns error", func() {
+		err := StreamServerInterceptor(nil, serverStream{}, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+			return errors.Input("foo")
+		})
+		Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		Ω(handled).Should(HaveLen(1))
+	})
+
+	It("Unary client", func() {
+		err := UnaryClientInterceptor(context.Background(), "/foo", nil, nil, nil,
+			func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				return ToStatus(errors.Input("foo")).Err()
+			})
+		Ω(errors.GetCode(err)).Should(Equal(errors.GeneralByInput))
+		Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+
+		var e *errors.Error
+		Ω(syserr.As(err, &e)).Should(BeTrue())
+		Ω(e.Remote()).Should(BeTrue())
+		Ω(errors.ForLog(err)).Should(HavePrefix("foo\nCode: GeneralByInput, CausedBy: ByInput\n"))
+	})
+
+	It("status.Code of client error", func() {
+		err := UnaryClientInterceptor(context.Background(), "/foo", nil, nil, nil,
+			func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				return status.Error(codes.NotFound, "foo")
+			})
+		Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		st, ok := status.FromError(err)
+		Ω(ok).Should(BeTrue())
+		Ω(st.Message()).Should(Equal("foo"))
+	})
+
+	It("Client error returned by server handled", func() {
+		remote := FromError(status.Error(codes.Unavailable, "down"))
+		_, err := unary(func(context.Context, interface{}) (interface{}, error) {
+			return nil, remote
+		})
+		Ω(handled).Should(Equal([]interface{}{remote}))
+		Ω(status.Code(err)).Should(Equal(codes.Unavailable))
+		Ω(status.Convert(err).Details()).ShouldNot(BeEmpty())
+	})
+
+	It("Stream client", func() {
+		s, err := StreamClientInterceptor(context.Background(), &grpc.StreamDesc{}, nil, "/foo",
+			func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+				return fakeClientStream{err: ToStatus(errors.External("foo")).Err()}, nil
+			})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(errors.GetCausedBy(s.RecvMsg(nil))).Should(Equal(errors.ByExternal))
+
+		s, _ = StreamClientInterceptor(context.Background(), &grpc.StreamDesc{}, nil, "/foo",
+			func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+				return fakeClientStream{err: io.EOF}, nil
+			})
+		Ω(s.RecvMsg(nil)).Should(Equal(io.EOF))
+	})
+
+})
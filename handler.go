@@ -4,32 +4,78 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"context"
 )
 
+// handlerSet is an immutable set of installed handlers, replaced as a whole
+// on change, Handle() loads it without lock.
+type handlerSet struct {
+	handler  Handler
+	handlers []*handlerEntry
+}
+
 var (
-	handler Handler = defaultHandler
+	// handlers stores *handlerSet.
+	handlers atomic.Value
+
+	// handlersLock serializes changes of handlers, not used by Handle().
+	handlersLock sync.Mutex
 )
 
+func init() {
+	handlers.Store(&handlerSet{handler: defaultHandler})
+}
+
 // Handler is a function do the actual error handling.
 type Handler func(ctx context.Context, err interface{})
 
+// Filter selects errors a handler added by AddHandler() handles, err is the
+// value passed to Handle(), after redaction.
+type Filter func(err interface{}) bool
+
+type handlerEntry struct {
+	filter Filter
+	h      Handler
+}
+
 // Handle use handler to handle non-nil err value. Use SetHandler() to switch
 // handler, default handler is a plain log.Print(), if ctx is nil, pass
-// context.Background() to error handler.
+// context.Background() to error handler. err is redacted by Redact() before
+// logging and passing to handler.
+//
+// Handler set by SetHandler() called first, then handlers added by
+// AddHandler() that accept err, in the order they added.
+//
+// If CaptureLabels enabled by SetCapture(), pprof labels of ctx are captured
+// to the first *Error in the wrap chain of the redacted err, if not
+// captured yet. err itself is not changed.
+//
+// Handle is safe to call concurrently, even with the same err, and with
+// setters of the package, such as SetScrubbers(), SetCapture() and
+// AddMessage().
 func Handle(ctx context.Context, err interface{}) {
-	log.Print(ForLog(err))
-
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	handler(ctx, err)
+
+	err = redact(err, ctxLabels(ctx))
+	log.Print(ForLog(err))
+
+	set := handlers.Load().(*handlerSet)
+	set.handler(ctx, err)
+	for _, entry := range set.handlers {
+		if entry.filter == nil || entry.filter(err) {
+			entry.h(ctx, err)
+		}
+	}
 }
 
-// SetHandler switch error handler, NOTE: no sync lock to internal handler
-// variable, only call SetHandler in application initialization code, to
-// prevent data race.
+// SetHandler switch error handler, called before handlers added by
+// AddHandler(). Safe to call concurrently with Handle(), Handle() calls
+// either the old or the new handler.
 // If h is nil, reset to default handler, this feature only available in test
 // mode for unit tests to override error handler.
 func SetHandler(h Handler) {
@@ -37,11 +83,90 @@ func SetHandler(h Handler) {
 		if !inTestMode() {
 			log.Panicf("[errors] Handler can not be nil")
 		}
-		handler = defaultHandler
-		return
+		h = defaultHandler
+	}
+
+	updateHandlers(func(set *handlerSet) {
+		set.handler = h
+	})
+}
+
+// AddHandler adds h to handle errors accepted by filter, nil filter accepts
+// all errors, returns a function removes the handler. Route errors to
+// different sinks by filters, such as report bugs to crash service, and
+// ByExternal errors to health monitoring:
+//
+//  errors.AddHandler(errors.CausedByFilter(errors.ByBug, errors.ByRuntime), reportCrash)
+//  errors.AddHandler(errors.CausedByFilter(errors.ByExternal), reportHealth)
+//
+// AddHandler and the remove function are safe to call concurrently, and
+// from handlers. Handle() running concurrently sees the handler set either
+// before or after the change, never partial.
+func AddHandler(filter Filter, h Handler) (remove func()) {
+	if h == nil {
+		log.Panicf("[errors] Handler can not be nil")
+	}
+
+	entry := &handlerEntry{filter, h}
+	updateHandlers(func(set *handlerSet) {
+		set.handlers = append(set.handlers, entry)
+	})
+	return func() {
+		updateHandlers(func(set *handlerSet) {
+			set.handlers = removeHandler(set.handlers, entry)
+		})
+	}
+}
+
+// updateHandlers replaces handlers with a copy modified by fn, fn can
+// replace or append slice field, but not modify its elements in place.
+func updateHandlers(fn func(set *handlerSet)) {
+	handlersLock.Lock()
+	defer handlersLock.Unlock()
+
+	set := *handlers.Load().(*handlerSet)
+	set.handlers = set.handlers[:len(set.handlers):len(set.handlers)]
+	fn(&set)
+	handlers.Store(&set)
+}
+
+// removeHandler returns a new slice of entries without entry.
+func removeHandler(entries []*handlerEntry, entry *handlerEntry) []*handlerEntry {
+	r := make([]*handlerEntry, 0, len(entries))
+	for _, e := range entries {
+		if e != entry {
+			r = append(r, e)
+		}
 	}
+	return r
+}
 
-	handler = h
+// CausedByFilter returns a Filter accepts errors caused by one of causes,
+// non-error values, such as panic values, are ByBug.
+func CausedByFilter(causes ...CausedBy) Filter {
+	return func(err interface{}) bool {
+		caused := GetPanicCausedBy(err)
+		for _, c := range causes {
+			if c == caused {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CodeFilter returns a Filter accepts errors has one of codes, see
+// GetCode().
+func CodeFilter(codes ...Code) Filter {
+	return func(err interface{}) bool {
+		code := GetCode(err)
+		for _, c := range codes {
+			if c == code {
+				return true
+			}
+		}
+		return false
+	}
 }
 
 func defaultHandler(ctx context.Context, err interface{}) {
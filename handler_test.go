@@ -6,6 +6,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/redforks/errors"
@@ -46,4 +53,204 @@ var _ = Describe("handler", func() {
 		Ω(called).Should(Equal(1))
 	})
 
+	Context("AddHandler", func() {
+		var calls []string
+
+		record := func(name string) errors.Handler {
+			return func(_ context.Context, err interface{}) {
+				calls = append(calls, name)
+			}
+		}
+
+		BeforeEach(func() {
+			calls = nil
+			errors.SetHandler(record("global"))
+		})
+
+		AfterEach(func() {
+			errors.SetHandler(nil)
+		})
+
+		It("Called in order", func() {
+			reset.Add(errors.AddHandler(nil, record("a")))
+			reset.Add(errors.AddHandler(nil, record("b")))
+			reset.Add(errors.AddHandler(nil, record("c")))
+
+			errors.Handle(context.TODO(), errors.Bug("foo"))
+			Ω(calls).Should(Equal([]string{"global", "a", "b", "c"}))
+		})
+
+		It("Route by CausedBy", func() {
+			reset.Add(errors.AddHandler(errors.CausedByFilter(errors.ByBug, errors.ByRuntime), record("crash")))
+			reset.Add(errors.AddHandler(errors.CausedByFilter(errors.ByExternal), record("health")))
+
+			errors.Handle(context.TODO(), errors.Bug("foo"))
+			errors.Handle(context.TODO(), errors.External("foo"))
+			errors.Handle(context.TODO(), errors.Input("foo"))
+			errors.Handle(context.TODO(), "panic value")
+			Ω(calls).Should(Equal([]string{"global", "crash", "global", "health", "global", "global", "crash"}))
+		})
+
+		It("Route by Code", func() {
+			code := errors.NewCode(errors.ByInput, 0x2101)
+			reset.Add(errors.AddHandler(errors.CodeFilter(code), record("code")))
+
+			errors.Handle(context.TODO(), errors.Input("foo"))
+			errors.Handle(context.TODO(), fmt.Errorf("wrap: %w", errors.Coded(code, "foo")))
+			Ω(calls).Should(Equal([]string{"global", "global", "code"}))
+		})
+
+		It("Remove", func() {
+			removeA := errors.AddHandler(nil, record("a"))
+			reset.Add(errors.AddHandler(nil, record("b")))
+			removeC := errors.AddHandler(nil, record("c"))
+
+			removeA()
+			removeA()
+			errors.Handle(context.TODO(), errors.Bug("foo"))
+			Ω(calls).Should(Equal([]string{"global", "b", "c"}))
+
+			calls = nil
+			removeC()
+			errors.Handle(context.TODO(), errors.Bug("foo"))
+			Ω(calls).Should(Equal([]string{"global", "b"}))
+		})
+
+		It("Remove while handling", func() {
+			var removeB func()
+			reset.Add(errors.AddHandler(nil, func(context.Context, interface{}) {
+				calls = append(calls, "a")
+				removeB()
+			}))
+			removeB = errors.AddHandler(nil, record("b"))
+
+			errors.Handle(context.TODO(), errors.Bug("foo"))
+			errors.Handle(context.TODO(), errors.Bug("foo"))
+			Ω(calls).Should(Equal([]string{"global", "a", "b", "global", "a"}))
+		})
+
+		It("Nil handler", func() {
+			Ω(func() {
+				errors.AddHandler(nil, nil)
+			}).Should(Panic())
+		})
+	})
+
+	Context("Concurrency", func() {
+		BeforeEach(func() {
+			log.SetOutput(ioutil.Discard)
+		})
+
+		AfterEach(func() {
+			log.SetOutput(os.Stderr)
+			errors.SetHandler(nil)
+		})
+
+		It("Change handlers while handling", func() {
+			var (
+				wg      sync.WaitGroup
+				handled int64
+				count   = func(context.Context, interface{}) {
+					atomic.AddInt64(&handled, 1)
+				}
+			)
+			reset.Add(errors.AddHandler(nil, count))
+
+			for i := 0; i < 4; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						errors.Handle(context.TODO(), errors.Bug("foo"))
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						remove := errors.AddHandler(errors.CausedByFilter(errors.ByBug), count)
+						errors.SetHandler(count)
+						remove()
+					}
+				}()
+			}
+			wg.Wait()
+
+			// handler added before handling always called
+			Ω(atomic.LoadInt64(&handled)).Should(BeNumerically(">=", 200))
+		})
+
+		It("Remove from handler", func() {
+			var (
+				wg     sync.WaitGroup
+				remove func()
+				once   sync.Once
+			)
+			remove = errors.AddHandler(nil, func(context.Context, interface{}) {
+				once.Do(func() {
+					remove()
+				})
+			})
+
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errors.Handle(context.TODO(), errors.Bug("foo"))
+				}()
+			}
+			wg.Wait()
+		})
+
+		It("Handle shared error", func() {
+			var (
+				wg      sync.WaitGroup
+				handled int64
+				code    = errors.NewCode(errors.ByInput, 0x3301)
+				e       = errors.Coded(code, "password=hunter2 rejected").With("token", "secret").With("user", 1)
+				ctx     = pprof.WithLabels(context.Background(), pprof.Labels("handler", "/order"))
+			)
+			defer errors.SetCapture(errors.CaptureTime)
+			defer errors.SetScrubbers(errors.ScrubTokens, errors.ScrubEmails)
+			errors.SetCapture(errors.CaptureTime | errors.CaptureLabels)
+			errors.SetHandler(func(ctx context.Context, err interface{}) {
+				_ = errors.UserMessage(ctx, err.(error))
+				_ = errors.Fingerprint(err)
+				_ = errors.GetAttrs(err)
+				atomic.AddInt64(&handled, 1)
+			})
+
+			for i := 0; i < 4; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						errors.Handle(ctx, e)
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						errors.SetScrubbers(errors.ScrubTokens)
+						errors.SetScrubbers(errors.ScrubTokens, errors.ScrubEmails)
+						errors.AddSensitiveKeys("test-handle-shared-error")
+						errors.SetCapture(errors.CaptureLabels)
+						errors.SetCapture(errors.CaptureTime | errors.CaptureLabels)
+						_ = errors.AddMessage("en", code, "Rejected")
+					}
+				}()
+			}
+			wg.Wait()
+
+			Ω(atomic.LoadInt64(&handled)).Should(Equal(int64(200)))
+			Ω(e.Error()).Should(Equal("password=hunter2 rejected"))
+			Ω(e.Labels()).Should(BeNil())
+
+			// changes undone by reset.Disable()
+			reset.Disable()
+			reset.Enable()
+			Ω(errors.UserMessage(nil, e)).Should(Equal(e.Error()))
+			Ω(errors.Redact(errors.Input("foo").With("test-handle-shared-error", "bar")).(*errors.Error).Attrs()).Should(Equal([]errors.Attr{{"test-handle-shared-error", "bar"}}))
+		})
+	})
+
 })
@@ -0,0 +1,123 @@
+// Package httperr handles errors in CausedBy way for net/http services.
+//
+// Recover() middleware recovers panics of http handlers, handles the error by
+// errors.Handle() with request context, and writes response with status code
+// follows CausedBy. Only public message of the error shown to the client,
+// error details of ByBug and ByRuntime errors are hidden, it is not their
+// fault.
+package httperr
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/redforks/errors"
+)
+
+// IDHeader is the response header carries errors.ErrorID() of the error, set
+// by Error() if the error has id.
+const IDHeader = "X-Error-Id"
+
+var (
+	statusLock   sync.RWMutex
+	statusByCode = map[errors.Code]int{}
+)
+
+// SetStatus overrides response status code of an error code, such as 422 for
+// a ByClientBug code, or 503 for a ByExternal code. Safe to call
+// concurrently with request handlers.
+func SetStatus(code errors.Code, status int) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+
+	statusByCode[code] = status
+}
+
+// StatusCode returns http status code of v:
+//
+//  ByInput, ByClientBug: 400 Bad Request
+//  ByExternal:           502 Bad Gateway
+//  ByBug, ByRuntime:     500 Internal Server Error
+//
+// Status set by SetStatus() takes precedence. Returns 200 if v is nil.
+func StatusCode(v interface{}) int {
+	code := errors.GetCode(v)
+	statusLock.RLock()
+	status, ok := statusByCode[code]
+	statusLock.RUnlock()
+	if ok {
+		return status
+	}
+
+	switch code.Caused() {
+	case errors.CausedBy(0):
+		return http.StatusOK
+	case errors.ByInput, errors.ByClientBug:
+		return http.StatusBadRequest
+	case errors.ByExternal:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error handles v by errors.Handle() with request context, then writes
+// response to client by renderer selected by Accept header. Only the message
+// for end-user and the error id are rendered, see errors.UserMessage() and
+// errors.ErrorID(), the user can report the id to find the error log.
+func Error(w http.ResponseWriter, r *http.Request, v interface{}) {
+	errors.Handle(r.Context(), v)
+
+	if id := errors.ErrorID(v); id != "" {
+		w.Header().Set(IDHeader, id)
+	}
+	status := StatusCode(v)
+	selectRenderer(r)(w, r, status, publicMessage(r, v), v)
+}
+
+// HandlerFunc is http handler function returns error, returned error handled
+// by Error(). Use with Recover() to handle panics too:
+//
+//  http.Handle("/", httperr.Recover(httperr.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//    if err := r.ParseForm(); err != nil {
+//      return errors.NewInput(err)
+//    }
+//    ...
+//  })))
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP implements http.Handler.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		Error(w, r, err)
+	}
+}
+
+// Recover returns a middleware recovers panics of next handler, and handles
+// the panic value by Error().
+//
+// http.ErrAbortHandler panics re-panicked, to abort the response as net/http
+// does.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			Error(w, r, v)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func publicMessage(r *http.Request, v interface{}) string {
+	if err, ok := v.(error); ok {
+		return errors.UserMessage(r.Context(), err)
+	}
+	return errors.Apology
+}
@@ -0,0 +1,104 @@
+package httperr
+
+import (
+	"context"
+	syserr "errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/redforks/testing/reset"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+type ctxKey struct{}
+
+var _ = Describe("httperr", func() {
+	var (
+		handled []interface{}
+		ctxs    []context.Context
+	)
+
+	BeforeEach(func() {
+		reset.Enable()
+		handled, ctxs = nil, nil
+		errors.SetHandler(func(ctx context.Context, err interface{}) {
+			handled = append(handled, err)
+			ctxs = append(ctxs, ctx)
+		})
+	})
+
+	AfterEach(func() {
+		errors.SetHandler(nil)
+		reset.Disable()
+	})
+
+	serve := func(h http.Handler) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), ctxKey{}, 1))
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	panicWith := func(v interface{}) http.Handler {
+		return Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(v)
+		}))
+	}
+
+	DescribeTable("Recover", func(v interface{}, status int, body string) {
+		w := serve(panicWith(v))
+		Ω(w.Code).Should(Equal(status))
+		if id := errors.ErrorID(v); id != "" {
+			body += "\nError ID: " + id
+		}
+		Ω(w.Body.String()).Should(Equal(body + "\n"))
+		Ω(w.Header().Get(IDHeader)).Should(Equal(errors.ErrorID(v)))
+		Ω(handled).Should(Equal([]interface{}{v}))
+		Ω(ctxs[0].Value(ctxKey{})).Should(Equal(1))
+	},
+		Entry("ByInput", errors.Input("bad"), 400, "bad"),
+		Entry("ByClientBug", errors.ClientBug("bad"), 400, "bad"),
+		Entry("ByExternal", errors.External("down"), 502, errors.ExternalMessage),
+		Entry("ByExternal public", errors.External("down").WithPublicMessage("Payment unavailable"), 502, "Payment unavailable"),
+		Entry("ByBug", errors.Bug("secret"), 500, errors.Apology),
+		Entry("ByRuntime", errors.Runtime("secret"), 500, errors.Apology),
+		Entry("plain error", syserr.New("secret"), 500, errors.Apology),
+		Entry("other value", 3, 500, errors.Apology),
+	)
+
+	It("No panic", func() {
+		w := serve(Recover(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		})))
+		Ω(w.Code).Should(Equal(200))
+		Ω(w.Body.String()).Should(Equal("ok"))
+		Ω(handled).Should(BeEmpty())
+	})
+
+	It("ErrAbortHandler", func() {
+		Ω(func() {
+			serve(panicWith(http.ErrAbortHandler))
+		}).Should(Panic())
+		Ω(handled).Should(BeEmpty())
+	})
+
+	It("SetStatus", func() {
+		code := errors.NewCode(errors.ByExternal, 0xff0001)
+		SetStatus(code, http.StatusServiceUnavailable)
+		defer delete(statusByCode, code)
+
+		Ω(StatusCode(errors.Coded(code, "foo"))).Should(Equal(503))
+		Ω(StatusCode(errors.External("foo"))).Should(Equal(502))
+	})
+
+	It("StatusCode of nil", func() {
+		Ω(StatusCode(nil)).Should(Equal(200))
+	})
+
+})
@@ -0,0 +1,162 @@
+package httperr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	syserr "errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/redforks/errors"
+)
+
+// ProblemMediaType is media type of RFC 7807 problem document.
+const ProblemMediaType = "application/problem+json"
+
+// problemTypeBase stores prefix of problem type URI, string.
+var problemTypeBase atomic.Value
+
+// Problem is RFC 7807 problem document, with extension member "code".
+type Problem struct {
+	// Type is SetProblemTypeBase() prefix followed by registered code name,
+	// "about:blank" if the code not registered.
+	Type string `json:"type"`
+
+	// Title is the message safe to show to client.
+	Title string `json:"title"`
+
+	Status int `json:"status"`
+
+	// Detail is the public message, only set for ByInput and ByClientBug
+	// errors.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance identifies this occurrence of the error, "urn:error-instance:"
+	// followed by errors.ErrorID(), or a random id if the error has no id.
+	Instance string `json:"instance,omitempty"`
+
+	// Code is the error code.
+	Code errors.Code `json:"code,omitempty"`
+
+	// Violations of errors.ValidationError, for front-end to highlight the
+	// offending fields.
+	Violations []errors.Violation `json:"violations,omitempty"`
+}
+
+func init() {
+	problemTypeBase.Store("urn:error:")
+	SetRenderer(ProblemMediaType, ProblemRenderer)
+}
+
+// SetProblemTypeBase set the prefix of problem type URI, default to
+// "urn:error:". Safe to call concurrently with request handlers.
+func SetProblemTypeBase(base string) {
+	problemTypeBase.Store(base)
+}
+
+// NewProblem creates problem document of error value v, title is the
+// message safe to show to client.
+func NewProblem(v interface{}, status int, title string) *Problem {
+	code := errors.GetCode(v)
+	p := &Problem{
+		Type:     "about:blank",
+		Title:    title,
+		Status:   status,
+		Instance: instanceID(v),
+		Code:     code,
+	}
+
+	if info, ok := errors.LookupCode(code); ok {
+		p.Type = problemTypeBase.Load().(string) + info.Name
+	}
+
+	switch code.Caused() {
+	case errors.ByInput, errors.ByClientBug:
+		p.Detail = title
+
+		var ve *errors.ValidationError
+		if syserr.As(v.(error), &ve) {
+			p.Violations = ve.Violations()
+		}
+	}
+	return p
+}
+
+// ProblemRenderer renders error as RFC 7807 problem document.
+func ProblemRenderer(w http.ResponseWriter, r *http.Request, status int, msg string, v interface{}) {
+	writeHeader(w, ProblemMediaType, status)
+	_ = json.NewEncoder(w).Encode(NewProblem(v, status, msg))
+}
+
+// ParseProblem parses problem document returned by a downstream service to
+// Error, see Problem.ToError(). Returns ByExternal error if r is not a
+// problem document.
+func ParseProblem(r io.Reader) (*errors.Error, error) {
+	var p Problem
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, errors.NewExternal(err)
+	}
+	return p.ToError(), nil
+}
+
+// ToError converts the problem document of a downstream service to Error.
+// Code resolved from "code" member, or problem type if it is a registered
+// code name, otherwise 4xx status as ByInput, others as ByExternal. Error
+// message is "detail" member, or "title" if "detail" is empty. Error id
+// restored from "instance" member if it is created by NewProblem().
+//
+// ByInput and ByClientBug errors of the downstream service are not caused by
+// our client, they are wrapped as ByExternal, Inner() returns the downstream
+// error with its code.
+func (p *Problem) ToError() *errors.Error {
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+
+	code := p.Code
+	if code == errors.NotError {
+		code = p.codeFromType()
+	}
+	e := errors.Coded(code, msg).With("problemType", p.Type).With("instance", p.Instance)
+	if strings.HasPrefix(p.Instance, instancePrefix) {
+		e = e.WithID(strings.TrimPrefix(p.Instance, instancePrefix))
+	}
+
+	switch code.Caused() {
+	case errors.ByInput, errors.ByClientBug:
+		return errors.NewExternal(e)
+	}
+	return e
+}
+
+func (p *Problem) codeFromType() errors.Code {
+	base := problemTypeBase.Load().(string)
+	if strings.HasPrefix(p.Type, base) {
+		if info, ok := errors.LookupCodeName(strings.TrimPrefix(p.Type, base)); ok {
+			return info.Code
+		}
+	}
+
+	if p.Status >= 400 && p.Status < 500 {
+		return errors.GeneralByInput
+	}
+	return errors.GeneralByExternal
+}
+
+const instancePrefix = "urn:error-instance:"
+
+func instanceID(v interface{}) string {
+	if id := errors.ErrorID(v); id != "" {
+		return instancePrefix + id
+	}
+
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		panic(errors.NewRuntime(err))
+	}
+	return instancePrefix + hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,141 @@
+package httperr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/redforks/testing/reset"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Problem", func() {
+	var cardDeclined = errors.MustRegisterCode(errors.NewCode(errors.ByInput, 0xff0101), "test.CardDeclined", "Card declined")
+
+	BeforeEach(func() {
+		reset.Enable()
+		errors.SetHandler(func(context.Context, interface{}) {})
+	})
+
+	AfterEach(func() {
+		errors.SetHandler(nil)
+		reset.Disable()
+	})
+
+	render := func(err error) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", ProblemMediaType)
+		Error(w, r, err)
+
+		var m map[string]interface{}
+		Ω(json.Unmarshal(w.Body.Bytes(), &m)).Should(Succeed())
+		return w, m
+	}
+
+	It("Registered code", func() {
+		w, m := render(errors.Coded(cardDeclined, "card 1234 declined"))
+		Ω(w.Code).Should(Equal(400))
+		Ω(w.Header().Get("Content-Type")).Should(Equal(ProblemMediaType))
+		Ω(m).Should(HaveKeyWithValue("type", "urn:error:test.CardDeclined"))
+		Ω(m).Should(HaveKeyWithValue("title", "card 1234 declined"))
+		Ω(m).Should(HaveKeyWithValue("status", float64(400)))
+		Ω(m).Should(HaveKeyWithValue("detail", "card 1234 declined"))
+		Ω(m).Should(HaveKeyWithValue("code", float64(cardDeclined)))
+		Ω(m["instance"]).ShouldNot(BeEmpty())
+	})
+
+	It("Bug hides detail", func() {
+		_, m := render(errors.Bug("secret"))
+		Ω(m).Should(HaveKeyWithValue("title", errors.Apology))
+		Ω(m).ShouldNot(HaveKey("detail"))
+		Ω(m).Should(HaveKeyWithValue("type", "urn:error:GeneralByBug"))
+	})
+
+	It("Violations", func() {
+		_, m := render(errors.NewValidation().Add("name", "required", "name is required", nil))
+		Ω(m).Should(HaveKeyWithValue("violations", []interface{}{
+			map[string]interface{}{"field": "name", "code": "required", "message": "name is required"},
+		}))
+	})
+
+	It("Public message", func() {
+		_, m := render(errors.Input("card 1234 declined").WithPublicMessage("card declined"))
+		Ω(m).Should(HaveKeyWithValue("title", "card declined"))
+		Ω(m).Should(HaveKeyWithValue("detail", "card declined"))
+	})
+
+	It("Not registered code", func() {
+		_, m := render(errors.Coded(errors.NewCode(errors.ByExternal, 0xff0102), "down"))
+		Ω(m).Should(HaveKeyWithValue("type", "about:blank"))
+	})
+
+	It("Instance unique", func() {
+		_, m1 := render(errors.Input("foo"))
+		_, m2 := render(errors.Input("foo"))
+		Ω(m1["instance"]).ShouldNot(Equal(m2["instance"]))
+
+		_, m := render(errors.Input("foo").WithID("id1"))
+		Ω(m).Should(HaveKeyWithValue("instance", "urn:error-instance:id1"))
+	})
+
+	It("Round trip", func() {
+		w, _ := render(errors.Coded(cardDeclined, "card declined"))
+		e, err := ParseProblem(bytes.NewReader(w.Body.Bytes()))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(e.Code()).Should(Equal(errors.GeneralByExternal))
+		Ω(e.Error()).Should(Equal("card declined"))
+
+		inner := e.Inner().(*errors.Error)
+		Ω(inner.Code()).Should(Equal(cardDeclined))
+		Ω(inner.ID()).Should(Equal(w.Header().Get(IDHeader)))
+	})
+
+	It("Downstream external error not wrapped", func() {
+		code := errors.NewCode(errors.ByExternal, 0xff0103)
+		w, _ := render(errors.Coded(code, "down"))
+		e, err := ParseProblem(bytes.NewReader(w.Body.Bytes()))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(e.Code()).Should(Equal(code))
+		Ω(e.ID()).Should(Equal(w.Header().Get(IDHeader)))
+	})
+
+	It("Code from type", func() {
+		e, err := ParseProblem(strings.NewReader(`{"type": "urn:error:test.CardDeclined", "title": "declined", "status": 400}`))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByExternal))
+		Ω(e.Inner().(*errors.Error).Code()).Should(Equal(cardDeclined))
+		Ω(e.Error()).Should(Equal("declined"))
+	})
+
+	It("Code from status", func() {
+		e, err := ParseProblem(strings.NewReader(`{"type": "https://example.com/probs/out-of-credit", "title": "out of credit", "status": 403}`))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByExternal))
+		Ω(errors.GetCausedBy(e.Inner())).Should(Equal(errors.ByInput))
+		Ω(e.Attrs()).Should(ContainElement(errors.Attr{Key: "problemType", Value: "https://example.com/probs/out-of-credit"}))
+
+		e, err = ParseProblem(strings.NewReader(`{"title": "oops", "status": 500}`))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByExternal))
+	})
+
+	It("Bad document", func() {
+		_, err := ParseProblem(strings.NewReader(`{`))
+		Ω(errors.GetCausedBy(err)).Should(Equal(errors.ByExternal))
+	})
+
+	It("NewProblem", func() {
+		p := NewProblem(errors.Input("foo"), http.StatusBadRequest, "foo")
+		Ω(p.Status).Should(Equal(400))
+		Ω(p.Detail).Should(Equal("foo"))
+	})
+
+})
@@ -0,0 +1,153 @@
+package httperr
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redforks/errors"
+)
+
+// Renderer writes error response, status is http status code, msg is the
+// message safe to show to client, v is the error value.
+//
+// Renderer should set Content-Type header and write status code.
+type Renderer func(w http.ResponseWriter, r *http.Request, status int, msg string, v interface{})
+
+const defaultMediaType = "text/plain"
+
+var (
+	renderersLock sync.RWMutex
+	renderers     = map[string]Renderer{
+		"text/plain":       TextRenderer,
+		"text/html":        HTMLRenderer,
+		"application/json": JSONRenderer,
+	}
+)
+
+// SetRenderer set renderer of a media type, such as "application/json",
+// renderer selected by request Accept header, default to "text/plain". Safe
+// to call concurrently with request handlers.
+func SetRenderer(mediaType string, renderer Renderer) {
+	renderersLock.Lock()
+	defer renderersLock.Unlock()
+
+	renderers[mediaType] = renderer
+}
+
+// TextRenderer renders error as plain text, followed by error id line if the
+// error has id.
+func TextRenderer(w http.ResponseWriter, r *http.Request, status int, msg string, v interface{}) {
+	if id := errors.ErrorID(v); id != "" {
+		msg += "\nError ID: " + id
+	}
+	http.Error(w, msg, status)
+}
+
+// HTMLRenderer renders error as a simple html page.
+func HTMLRenderer(w http.ResponseWriter, r *http.Request, status int, msg string, v interface{}) {
+	idPart := ""
+	if id := errors.ErrorID(v); id != "" {
+		idPart = "<p>Error ID: <code>" + html.EscapeString(id) + "</code></p>"
+	}
+
+	writeHeader(w, "text/html; charset=utf-8", status)
+	_, _ = fmt.Fprintf(w, "<!DOCTYPE html>\n<html><head><title>%d %s</title></head><body><h1>%s</h1><p>%s</p>%s</body></html>\n",
+		status, html.EscapeString(http.StatusText(status)), html.EscapeString(http.StatusText(status)), html.EscapeString(msg), idPart)
+}
+
+// JSONRenderer renders error as json object, "id" omitted if the error has
+// no id:
+//
+//  {"status": 400, "message": "bad input", "id": "01ARZ3NDEKTSV4RRFFQ69G5FAV"}
+func JSONRenderer(w http.ResponseWriter, r *http.Request, status int, msg string, v interface{}) {
+	writeHeader(w, "application/json", status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+		ID      string `json:"id,omitempty"`
+	}{status, msg, errors.ErrorID(v)})
+}
+
+func writeHeader(w http.ResponseWriter, contentType string, status int) {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+}
+
+type acceptItem struct {
+	mediaType string
+	q         float64
+}
+
+// selectRenderer selects renderer by Accept header of the request.
+func selectRenderer(r *http.Request) Renderer {
+	var items []acceptItem
+	for _, s := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(s))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			items = append(items, acceptItem{mediaType, q})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	renderersLock.RLock()
+	defer renderersLock.RUnlock()
+
+	for _, item := range items {
+		if renderer, ok := renderers[item.mediaType]; ok {
+			return renderer
+		}
+
+		switch {
+		case item.mediaType == "*/*":
+			return renderers[defaultMediaType]
+		case strings.HasSuffix(item.mediaType, "/*"):
+			prefix := strings.TrimSuffix(item.mediaType, "*")
+			if strings.HasPrefix(defaultMediaType, prefix) {
+				return renderers[defaultMediaType]
+			}
+			if mediaType := firstWithPrefix(prefix); mediaType != "" {
+				return renderers[mediaType]
+			}
+		}
+	}
+	return renderers[defaultMediaType]
+}
+
+// firstWithPrefix returns the first media type in alphabet order that has
+// renderer and starts with prefix, returns "" if not found. Caller must hold
+// renderersLock.
+func firstWithPrefix(prefix string) string {
+	var r []string
+	for mediaType := range renderers {
+		if strings.HasPrefix(mediaType, prefix) {
+			r = append(r, mediaType)
+		}
+	}
+	if len(r) == 0 {
+		return ""
+	}
+
+	sort.Strings(r)
+	return r[0]
+}
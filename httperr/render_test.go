@@ -0,0 +1,111 @@
+package httperr
+
+import (
+	"context"
+	syserr "errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/redforks/testing/reset"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("HandlerFunc", func() {
+	var handled []interface{}
+
+	BeforeEach(func() {
+		reset.Enable()
+		handled = nil
+		errors.SetHandler(func(_ context.Context, err interface{}) {
+			handled = append(handled, err)
+		})
+	})
+
+	AfterEach(func() {
+		errors.SetHandler(nil)
+		reset.Disable()
+	})
+
+	serve := func(h http.Handler, accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	returns := func(err error) http.Handler {
+		return HandlerFunc(func(w http.ResponseWriter, _ *http.Request) error {
+			if err == nil {
+				_, _ = w.Write([]byte("ok"))
+			}
+			return err
+		})
+	}
+
+	It("No error", func() {
+		w := serve(returns(nil), "")
+		Ω(w.Code).Should(Equal(200))
+		Ω(w.Body.String()).Should(Equal("ok"))
+		Ω(handled).Should(BeEmpty())
+	})
+
+	It("Error", func() {
+		err := errors.Input("bad")
+		w := serve(returns(err), "")
+		Ω(w.Code).Should(Equal(400))
+		Ω(w.Header().Get("Content-Type")).Should(HavePrefix("text/plain"))
+		Ω(w.Body.String()).Should(Equal("bad\nError ID: " + err.ID() + "\n"))
+		Ω(handled).Should(Equal([]interface{}{err}))
+	})
+
+	DescribeTable("Select renderer by Accept", func(accept, contentType string) {
+		w := serve(returns(errors.Bug("secret")), accept)
+		Ω(w.Code).Should(Equal(500))
+		Ω(w.Header().Get("Content-Type")).Should(HavePrefix(contentType))
+		Ω(w.Body.String()).ShouldNot(ContainSubstring("secret"))
+	},
+		Entry("no accept", "", "text/plain"),
+		Entry("json", "application/json", "application/json"),
+		Entry("html", "text/html,application/xhtml+xml,*/*;q=0.8", "text/html"),
+		Entry("q order", "text/html;q=0.5, application/json", "application/json"),
+		Entry("q zero", "application/json;q=0, text/html", "text/html"),
+		Entry("any", "*/*", "text/plain"),
+		Entry("wildcard", "application/*", "application/json"),
+		Entry("unknown", "image/png", "text/plain"),
+		Entry("malformed", ";;;", "text/plain"),
+	)
+
+	It("JSON body", func() {
+		w := serve(returns(errors.Input("bad").WithID("id1")), "application/json")
+		Ω(w.Body.String()).Should(MatchJSON(`{"status": 400, "message": "bad", "id": "id1"}`))
+
+		w = serve(returns(syserr.New("bad")), "application/json")
+		Ω(w.Body.String()).Should(MatchJSON(`{"status": 500, "message": "` + errors.Apology + `"}`))
+	})
+
+	It("HTML escaped", func() {
+		w := serve(returns(errors.Input("<b>")), "text/html")
+		Ω(w.Body.String()).Should(ContainSubstring("&lt;b&gt;"))
+		Ω(w.Body.String()).Should(ContainSubstring("<p>Error ID: <code>"))
+	})
+
+	It("SetRenderer", func() {
+		SetRenderer("text/csv", func(w http.ResponseWriter, _ *http.Request, status int, msg string, _ interface{}) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("csv," + msg))
+		})
+		defer delete(renderers, "text/csv")
+
+		w := serve(returns(errors.Input("bad")), "text/csv")
+		Ω(w.Body.String()).Should(Equal("csv,bad"))
+	})
+
+})
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator generates unique id of Error.
+type IDGenerator func() string
+
+var (
+	// idGenerator stores IDGenerator.
+	idGenerator atomic.Value
+
+	idPrefix  uint16
+	idCounter uint64
+)
+
+// crockford is Crockford's base32 alphabet, used by ULID.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func init() {
+	idGenerator.Store(IDGenerator(NewID))
+
+	var buf [10]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		binary.BigEndian.PutUint64(buf[2:], uint64(time.Now().UnixNano()))
+	}
+	idPrefix = binary.BigEndian.Uint16(buf[:])
+	idCounter = binary.BigEndian.Uint64(buf[2:])
+}
+
+// SetIDGenerator set generator of Error ids, such as a generator returns
+// fixed ids in unit tests. If g is nil, reset to NewID.
+func SetIDGenerator(g IDGenerator) {
+	if g == nil {
+		g = NewID
+	}
+	idGenerator.Store(g)
+}
+
+// NewID is the default IDGenerator, generates 26 characters ULID compatible
+// id, such as "01ARZ3NDEKTSV4RRFFQ69G5FAV". Ids are sortable by creation
+// time, ids created in the same millisecond by the same process are sorted
+// by creation order.
+func NewID() string {
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	return encodeID(ms, idPrefix, atomic.AddUint64(&idCounter, 1))
+}
+
+// encodeID encodes 128 bits id: 48 bits time in milliseconds, 16 bits
+// per-process random prefix, and 64 bits counter, in Crockford's base32.
+func encodeID(ms uint64, prefix uint16, n uint64) string {
+	var r [26]byte
+	hi, lo := ms<<16|uint64(prefix), n
+	for i := len(r) - 1; i >= 0; i-- {
+		r[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(r[:])
+}
+
+// ID returns unique id of the error, assigned at creation, generated by
+// IDGenerator set by SetIDGenerator(). Log the id and show it to the user, to
+// find the log of the error the user reported.
+func (err *Error) ID() string {
+	return err.id
+}
+
+// WithID returns a copy of the error with id overridden, such as restoring
+// the id of a remote error. err itself is not changed, same as With().
+func (err *Error) WithID(id string) *Error {
+	r := err.clone()
+	r.id = id
+	return r
+}
+
+// ErrorID returns id of the outer most error has id in the wrap chain of v,
+// returns "" if not found, such as v is not an error, or not wraps *Error.
+func ErrorID(v interface{}) string {
+	var r string
+	e, _ := v.(error)
+	walk(e, func(e error) bool {
+		if ie, ok := e.(interface{ ID() string }); ok {
+			r = ie.ID()
+		}
+		return r != ""
+	})
+	return r
+}
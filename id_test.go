@@ -0,0 +1,89 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("ID", func() {
+
+	AfterEach(func() {
+		errors.SetIDGenerator(nil)
+	})
+
+	It("NewID unique and sortable", func() {
+		ids := make([]string, 1000)
+		for i := range ids {
+			ids[i] = errors.NewID()
+		}
+
+		Ω(ids[0]).Should(MatchRegexp("^[0-9A-HJKMNP-TV-Z]{26}$"))
+		Ω(sort.StringsAreSorted(ids)).Should(BeTrue())
+
+		set := map[string]bool{}
+		for _, id := range ids {
+			set[id] = true
+		}
+		Ω(set).Should(HaveLen(len(ids)))
+	})
+
+	It("Assigned at creation", func() {
+		a, b := errors.Bug("foo"), errors.NewInput(io.EOF)
+		Ω(a.ID()).ShouldNot(BeEmpty())
+		Ω(a.ID() < b.ID()).Should(BeTrue())
+		Ω(errors.ForLog(a)).Should(ContainSubstring("\nID: " + a.ID() + "\n"))
+	})
+
+	It("SetIDGenerator", func() {
+		n := 0
+		errors.SetIDGenerator(func() string {
+			n++
+			return fmt.Sprintf("id%d", n)
+		})
+
+		Ω(errors.Bug("foo").ID()).Should(Equal("id1"))
+		Ω(errors.NewValidation().ID()).Should(Equal("id2"))
+
+		errors.SetIDGenerator(nil)
+		Ω(errors.Bug("foo").ID()).Should(HaveLen(26))
+	})
+
+	It("ErrorID", func() {
+		inner := errors.Input("foo")
+		outer := errors.Wrap(errors.ByExternal, inner, "bar")
+		Ω(errors.ErrorID(outer)).Should(Equal(outer.ID()))
+		Ω(errors.ErrorID(fmt.Errorf("ctx: %w", inner))).Should(Equal(inner.ID()))
+		Ω(errors.ErrorID(io.EOF)).Should(BeEmpty())
+		Ω(errors.ErrorID(3)).Should(BeEmpty())
+		Ω(errors.ErrorID(nil)).Should(BeEmpty())
+	})
+
+	It("WithID", func() {
+		e := errors.Bug("foo")
+		Ω(e.WithID("remote").ID()).Should(Equal("remote"))
+		Ω(e.ID()).ShouldNot(Equal("remote"))
+	})
+
+	It("JSON", func() {
+		e := errors.Bug("foo")
+		data, err := json.Marshal(e)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var r errors.Error
+		Ω(json.Unmarshal(data, &r)).Should(Succeed())
+		Ω(r.ID()).Should(Equal(e.ID()))
+	})
+
+	It("Kept by Redact", func() {
+		e := errors.Bug("password=hunter2")
+		Ω(errors.Redact(e).(*errors.Error).ID()).Should(Equal(e.ID()))
+	})
+
+})
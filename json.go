@@ -0,0 +1,234 @@
+package errors
+
+import (
+	"encoding/json"
+	syserr "errors"
+	"time"
+)
+
+// jsonError is the JSON schema of Error:
+//
+//  {
+//    "id": "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+//    "message": "error message",
+//    "publicMessage": "message safe to show to end-user",
+//    "code": 67108864,
+//    "codeName": "GeneralByInput",
+//    "causedBy": "ByInput",
+//    "frames": [
+//      {"file": "/src/foo.go", "line": 12, "function": "Foo", "package": "github.com/foo"}
+//    ],
+//    "attributes": [{"key": "id", "value": 1}],
+//    "time": "2020-08-06T14:16:21.123456789+08:00",
+//    "goroutine": 18,
+//    "labels": {"handler": "/api/order"},
+//    "inner": {...},
+//    "errors": [{...}]
+//  }
+//
+// Errors not CausedByError only have "message" field, "inner" omitted if the
+// error wraps nothing, or the message comes from the wrapped plain error.
+// "errors" are errors wrapped by Unwrap() []error, such as errors of List.
+// ValidationError encodes its violations in "violations" field.
+type jsonError struct {
+	ID         string            `json:"id,omitempty"`
+	Message    string            `json:"message"`
+	Public     string            `json:"publicMessage,omitempty"`
+	Code       *Code             `json:"code,omitempty"`
+	CodeName   string            `json:"codeName,omitempty"`
+	CausedBy   string            `json:"causedBy,omitempty"`
+	Frames     []jsonFrame       `json:"frames,omitempty"`
+	Attributes []jsonAttr        `json:"attributes,omitempty"`
+	Time       *time.Time        `json:"time,omitempty"`
+	Goroutine  uint64            `json:"goroutine,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Inner      *jsonError        `json:"inner,omitempty"`
+	Errors     []*jsonError      `json:"errors,omitempty"`
+	Violations []Violation       `json:"violations,omitempty"`
+}
+
+type jsonFrame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+	Package  string `json:"package,omitempty"`
+}
+
+type jsonAttr struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+var (
+	_ json.Marshaler   = &Error{}
+	_ json.Unmarshaler = &Error{}
+)
+
+// MarshalJSON implements json.Marshaler, encodes message, code, stack frames,
+// attributes and inner errors.
+func (err *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONError(err))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reconstructs Error encoded by
+// MarshalJSON(). Stack frames are restored, but not program counters,
+// attribute values are decoded as encoding/json does for interface{}.
+//
+// If "code" field not exist, code resolved from "causedBy" field, default to
+// GeneralByBug.
+func (err *Error) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if e := json.Unmarshal(data, &je); e != nil {
+		return NewInput(e)
+	}
+
+	*err = *fromJSONError(&je)
+	return nil
+}
+
+func toJSONError(e error) *jsonError {
+	if ve, ok := e.(*ValidationError); ok {
+		return toJSONValidation(ve)
+	}
+
+	r := &jsonError{Message: e.Error()}
+	if multi, ok := e.(interface{ Unwrap() []error }); ok {
+		for _, err := range multi.Unwrap() {
+			r.Errors = append(r.Errors, toJSONError(err))
+		}
+	}
+
+	ce, ok := e.(CausedByError)
+	if !ok {
+		if inner := unwrap(e); inner != nil {
+			r.Inner = toJSONError(inner)
+		}
+		return r
+	}
+
+	code := ce.Code()
+	r.Code = &code
+	r.CausedBy = code.Caused().String()
+	if info, ok := LookupCode(code); ok {
+		r.CodeName = info.Name
+	}
+
+	if er, ok := e.(*Error); ok {
+		r.ID, r.Public = er.id, er.public
+		for _, frame := range er.StackFrames() {
+			r.Frames = append(r.Frames, jsonFrame{frame.File, frame.LineNumber, frame.Name, frame.Package})
+		}
+		for _, attr := range er.attrs {
+			r.Attributes = append(r.Attributes, jsonAttr(attr))
+		}
+		if !er.time.IsZero() {
+			r.Time = &er.time
+		}
+		r.Goroutine, r.Labels = er.goroutine, er.labels
+	}
+
+	if inner := ce.Inner(); inner != nil && !isPlainMessage(e, inner) {
+		r.Inner = toJSONError(inner)
+	}
+	return r
+}
+
+// isPlainMessage returns true if inner is a plain error that e's message
+// comes from, such as errors created by Input("foo").
+func isPlainMessage(e, inner error) bool {
+	if _, ok := inner.(CausedByError); ok {
+		return false
+	}
+	return unwrap(inner) == nil && inner.Error() == e.Error()
+}
+
+func fromJSONError(je *jsonError) *Error {
+	var code Code
+	switch {
+	case je.Code != nil:
+		code = *je.Code
+	default:
+		causedBy, ok := parseCausedBy(je.CausedBy)
+		if !ok {
+			causedBy = ByBug
+		}
+		code = Code(causedBy)
+	}
+
+	r := &Error{code: code, stack: &stack{frames: []StackFrame{}}, public: je.Public, id: je.ID}
+	if je.Inner == nil {
+		r.Err = syserr.New(je.Message)
+	} else {
+		r.Err = fromJSONInner(je.Inner)
+		if r.Err.Error() != je.Message {
+			r.msg = je.Message
+		}
+	}
+
+	for _, f := range je.Frames {
+		r.stack.frames = append(r.stack.frames, StackFrame{File: f.File, LineNumber: f.Line, Name: f.Function, Package: f.Package})
+	}
+	for _, attr := range je.Attributes {
+		r.attrs = append(r.attrs, Attr(attr))
+	}
+	if je.Time != nil {
+		r.time = *je.Time
+	}
+	r.goroutine, r.labels = je.Goroutine, je.Labels
+	return r
+}
+
+func fromJSONInner(je *jsonError) error {
+	if je.Violations != nil {
+		return fromJSONValidation(je)
+	}
+	if len(je.Errors) != 0 {
+		errs := make([]error, len(je.Errors))
+		for i, e := range je.Errors {
+			errs[i] = fromJSONInner(e)
+		}
+		if je.Code != nil || je.CausedBy != "" {
+			return &List{errs}
+		}
+		return &joinError{je.Message, errs}
+	}
+
+	if je.Code != nil || je.CausedBy != "" {
+		return fromJSONError(je)
+	}
+
+	if je.Inner == nil {
+		return syserr.New(je.Message)
+	}
+	return &wrapError{je.Message, fromJSONInner(je.Inner)}
+}
+
+// wrapError is a plain error wraps another error, such as the one created by
+// fmt.Errorf("%w").
+type wrapError struct {
+	msg string
+	err error
+}
+
+func (e *wrapError) Error() string {
+	return e.msg
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+// joinError is a plain error wraps multiple errors, such as the one created
+// by errors.Join() of go 1.20.
+type joinError struct {
+	msg  string
+	errs []error
+}
+
+func (e *joinError) Error() string {
+	return e.msg
+}
+
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
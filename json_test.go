@@ -0,0 +1,122 @@
+package errors_test
+
+import (
+	"encoding/json"
+	syserr "errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("JSON", func() {
+
+	roundTrip := func(e error) *errors.Error {
+		data, err := json.Marshal(e)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var r errors.Error
+		Ω(json.Unmarshal(data, &r)).Should(Succeed())
+		return &r
+	}
+
+	It("Schema", func() {
+		e := errors.Input("foo").With("id", 1)
+		data, err := json.Marshal(e)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var m map[string]interface{}
+		Ω(json.Unmarshal(data, &m)).Should(Succeed())
+		Ω(m).Should(HaveKeyWithValue("message", "foo"))
+		Ω(m).Should(HaveKeyWithValue("code", float64(errors.GeneralByInput)))
+		Ω(m).Should(HaveKeyWithValue("codeName", "GeneralByInput"))
+		Ω(m).Should(HaveKeyWithValue("causedBy", "ByInput"))
+		Ω(m).Should(HaveKeyWithValue("attributes", []interface{}{
+			map[string]interface{}{"key": "id", "value": float64(1)},
+		}))
+		Ω(m).ShouldNot(HaveKey("inner"))
+
+		frame := m["frames"].([]interface{})[0].(map[string]interface{})
+		Ω(frame["file"]).Should(HaveSuffix("json_test.go"))
+		Ω(frame).Should(HaveKey("line"))
+		Ω(frame).Should(HaveKey("function"))
+	})
+
+	It("Round trip", func() {
+		e := errors.Input("foo").With("id", "bar")
+		r := roundTrip(e)
+		Ω(r.Error()).Should(Equal("foo"))
+		Ω(errors.GetCausedBy(r)).Should(Equal(errors.ByInput))
+		Ω(errors.GetCode(r)).Should(Equal(errors.GeneralByInput))
+		Ω(r.Attrs()).Should(Equal([]errors.Attr{{"id", "bar"}}))
+		Ω(r.StackFrames()).Should(HaveLen(len(e.StackFrames())))
+		for i, frame := range e.StackFrames() {
+			frame.ProgramCounter = 0
+			Ω(r.StackFrames()[i]).Should(Equal(frame))
+		}
+	})
+
+	It("Inner chain", func() {
+		e := errors.Wrap(errors.ByExternal, fmt.Errorf("ctx: %w", errors.Input("foo")), "bar")
+		r := roundTrip(e)
+		Ω(r.Error()).Should(Equal("bar"))
+		Ω(errors.GetCausedBy(r)).Should(Equal(errors.ByExternal))
+
+		wrapper := r.Inner()
+		Ω(wrapper.Error()).Should(Equal("ctx: foo"))
+		Ω(errors.GetCausedBy(wrapper)).Should(Equal(errors.ByInput))
+
+		inner := syserr.Unwrap(wrapper)
+		Ω(inner.Error()).Should(Equal("foo"))
+		Ω(errors.GetCausedBy(inner)).Should(Equal(errors.ByInput))
+	})
+
+	It("List", func() {
+		code := errors.NewCode(errors.ByInput, 0x3201)
+		l := errors.Join(errors.Coded(code, "a").With("id", 1), errors.Bug("b"), syserr.New("c"))
+		data, err := json.Marshal(l)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var r errors.List
+		Ω(json.Unmarshal(data, &r)).Should(Succeed())
+		Ω(r.Error()).Should(Equal(l.Error()))
+		Ω(r.Code()).Should(Equal(errors.GeneralByBug))
+		Ω(r.Errors()).Should(HaveLen(3))
+		Ω(errors.GetCode(r.Errors()[0])).Should(Equal(code))
+		Ω(errors.GetAttrs(r.Errors()[0])).Should(Equal([]errors.Attr{{"id", float64(1)}}))
+		Ω(errors.GetCausedBy(r.Errors()[2])).Should(Equal(errors.ByBug))
+	})
+
+	It("Multi errors in inner chain", func() {
+		e := errors.Wrap(errors.ByExternal, errors.Join(errors.Input("a"), errors.Bug("b")), "batch failed")
+		r := roundTrip(e)
+		Ω(r.Error()).Should(Equal("batch failed"))
+
+		l, ok := r.Inner().(*errors.List)
+		Ω(ok).Should(BeTrue())
+		Ω(l.Error()).Should(Equal("a\nb"))
+		Ω(errors.GetCausedBy(l.Errors()[1])).Should(Equal(errors.ByBug))
+
+		e = errors.Wrap(errors.ByExternal, syserr.Join(errors.Input("a"), syserr.New("b")), "batch failed")
+		r = roundTrip(e)
+		inner := r.Inner().(interface{ Unwrap() []error })
+		Ω(r.Inner().Error()).Should(Equal("a\nb"))
+		Ω(inner.Unwrap()).Should(HaveLen(2))
+		Ω(errors.GetCausedBy(inner.Unwrap()[0])).Should(Equal(errors.ByInput))
+	})
+
+	It("CausedBy without code", func() {
+		var r errors.Error
+		Ω(json.Unmarshal([]byte(`{"message": "foo", "causedBy": "ByExternal"}`), &r)).Should(Succeed())
+		Ω(r.Error()).Should(Equal("foo"))
+		Ω(errors.GetCode(&r)).Should(Equal(errors.GeneralByExternal))
+	})
+
+	It("Bad json", func() {
+		var r errors.Error
+		Ω(json.Unmarshal([]byte(`{"message": 1}`), &r)).ShouldNot(Succeed())
+	})
+
+})
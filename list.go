@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// List aggregates multiple errors, such as errors of a batch job, or
+// validation errors. Zero value List is ready to use:
+//
+//  var errs errors.List
+//  for _, item := range items {
+//    errs.Append(process(item))
+//  }
+//  return errs.Err()
+//
+// CausedBy of List is the worst CausedBy of its errors, in order:
+// ByBug > ByRuntime > ByExternal > ByClientBug > ByInput.
+type List struct {
+	errs []error
+}
+
+var _ CausedByError = &List{}
+
+// Join returns a List contains non-nil errs, returns nil if no non-nil
+// error.
+func Join(errs ...error) error {
+	var l List
+	for _, err := range errs {
+		l.Append(err)
+	}
+	return l.Err()
+}
+
+// Append err to the list, nil err ignored.
+func (l *List) Append(err error) {
+	if err != nil {
+		l.errs = append(l.errs, err)
+	}
+}
+
+// Len returns number of errors in the list.
+func (l *List) Len() int {
+	return len(l.errs)
+}
+
+// Errors returns errors in the list.
+func (l *List) Errors() []error {
+	return l.errs
+}
+
+// Err returns nil if the list is empty, otherwise returns the list itself.
+func (l *List) Err() error {
+	if len(l.errs) == 0 {
+		return nil
+	}
+	return l
+}
+
+func (l *List) Error() string {
+	msgs := make([]string, len(l.errs))
+	for i, err := range l.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Code returns code of the first error has the worst CausedBy.
+func (l *List) Code() Code {
+	r := NotError
+	for _, err := range l.errs {
+		code := GetCode(err)
+		if r == NotError || severity(code.Caused()) > severity(r.Caused()) {
+			r = code
+		}
+	}
+	return r
+}
+
+// Inner returns nil, use Unwrap() to get the errors, implements
+// CausedByError interface.
+func (l *List) Inner() error {
+	return nil
+}
+
+// Unwrap returns errors in the list, work with errors.Is() and errors.As()
+// of go 1.20.
+func (l *List) Unwrap() []error {
+	return l.errs
+}
+
+// ErrorStack returns ErrorStack() of each error in the list.
+func (l *List) ErrorStack() string {
+	buf := bytes.Buffer{}
+	for i, err := range l.errs {
+		if i != 0 {
+			buf.WriteByte('\n')
+		}
+		if e, ok := err.(CausedByError); ok {
+			buf.WriteString(e.ErrorStack())
+		} else {
+			buf.WriteString(err.Error())
+		}
+	}
+	return buf.String()
+}
+
+// MarshalJSON implements json.Marshaler, same schema as Error, errors in the
+// list encoded in "errors" field.
+func (l *List) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONError(l))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reconstructs List encoded by
+// MarshalJSON().
+func (l *List) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return NewInput(err)
+	}
+
+	l.errs = make([]error, len(je.Errors))
+	for i, e := range je.Errors {
+		l.errs[i] = fromJSONInner(e)
+	}
+	return nil
+}
+
+func (l *List) forLog() string {
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "%d errors, Code: %s, CausedBy: %s", len(l.errs), l.Code(), l.Code().Caused())
+	for i, err := range l.errs {
+		fmt.Fprintf(&buf, "\n[%d] %s", i, ForLog(err))
+	}
+	return buf.String()
+}
+
+func severity(causedBy CausedBy) int {
+	switch causedBy {
+	case ByBug:
+		return 5
+	case ByRuntime:
+		return 4
+	case ByExternal:
+		return 3
+	case ByClientBug:
+		return 2
+	case ByInput:
+		return 1
+	default:
+		return 0
+	}
+}
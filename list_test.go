@@ -0,0 +1,74 @@
+package errors_test
+
+import (
+	syserr "errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("List", func() {
+
+	It("Empty", func() {
+		var l errors.List
+		Ω(l.Len()).Should(Equal(0))
+		Ω(l.Err()).Should(BeNil())
+		Ω(errors.Join()).Should(BeNil())
+		Ω(errors.Join(nil, nil)).Should(BeNil())
+	})
+
+	It("Append", func() {
+		var l errors.List
+		foo, bar := errors.Input("foo"), syserr.New("bar")
+		l.Append(foo)
+		l.Append(nil)
+		l.Append(bar)
+		Ω(l.Len()).Should(Equal(2))
+		Ω(l.Errors()).Should(Equal([]error{foo, bar}))
+		Ω(l.Err()).Should(BeIdenticalTo(&l))
+		Ω(l.Error()).Should(Equal("foo\nbar"))
+	})
+
+	DescribeTable("Worst CausedBy", func(exp errors.CausedBy, errs ...error) {
+		err := errors.Join(errs...)
+		Ω(errors.GetCausedBy(err)).Should(Equal(exp))
+	},
+		Entry("Input", errors.ByInput, errors.Input("a"), errors.Input("b")),
+		Entry("ClientBug", errors.ByClientBug, errors.Input("a"), errors.ClientBug("b")),
+		Entry("External", errors.ByExternal, errors.External("a"), errors.ClientBug("b")),
+		Entry("Runtime", errors.ByRuntime, errors.External("a"), errors.Runtime("b")),
+		Entry("Bug", errors.ByBug, errors.Runtime("a"), errors.Bug("b"), errors.Input("c")),
+		Entry("plain error", errors.ByBug, errors.Input("a"), syserr.New("b")),
+	)
+
+	It("Code of first worst error", func() {
+		code := errors.NewCode(errors.ByExternal, 0x21)
+		err := errors.Join(errors.Input("a"), errors.Coded(code, "b"), errors.External("c"))
+		Ω(errors.GetCode(err)).Should(Equal(code))
+	})
+
+	It("Unwrap", func() {
+		target := errors.Input("foo")
+		err := fmt.Errorf("ctx: %w", errors.Join(syserr.New("a"), target))
+		Ω(syserr.Is(err, target)).Should(BeTrue())
+	})
+
+	It("ForLog", func() {
+		s := errors.ForLog(errors.Join(errors.Input("foo"), syserr.New("bar")))
+		Ω(s).Should(HavePrefix("2 errors, Code: GeneralByBug, CausedBy: ByBug\n[0] foo\n"))
+		Ω(s).Should(ContainSubstring("list_test.go"))
+		Ω(s).Should(HaveSuffix("\n[1] bar"))
+	})
+
+	It("ErrorStack", func() {
+		s := errors.Join(errors.Input("foo"), syserr.New("bar")).(*errors.List).ErrorStack()
+		Ω(s).Should(HavePrefix("foo\n"))
+		Ω(s).Should(ContainSubstring("list_test.go"))
+		Ω(s).Should(HaveSuffix("\nbar"))
+	})
+
+})
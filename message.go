@@ -0,0 +1,210 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+
+	"github.com/redforks/testing/reset"
+)
+
+// Apology is the default user message of ByBug and ByRuntime errors, if no
+// message defined in catalog for the code or GeneralByBug/GeneralByRuntime.
+const Apology = "Sorry, something went wrong on our side, please try again later."
+
+type localeKey struct{}
+
+var (
+	catalogLock sync.RWMutex
+	catalog     = map[string]map[Code]*template.Template{}
+
+	// defaultLocale stores string.
+	defaultLocale atomic.Value
+)
+
+func init() {
+	defaultLocale.Store("en")
+}
+
+// WithLocale returns a context carries locale, such as "zh-CN", used by
+// UserMessage().
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFrom returns locale set by WithLocale(), returns default locale if
+// not set.
+func LocaleFrom(ctx context.Context) string {
+	if ctx != nil {
+		if locale, ok := ctx.Value(localeKey{}).(string); ok {
+			return locale
+		}
+	}
+	return defaultLocale.Load().(string)
+}
+
+// SetDefaultLocale set the locale used if locale not set in context, and the
+// last fallback locale, default to "en".
+func SetDefaultLocale(locale string) {
+	defaultLocale.Store(locale)
+}
+
+// AddMessage adds user message of code in locale to catalog. text is a
+// text/template, executed with attributes of the error as a map, such as
+// "Card ending {{.last4}} declined". Safe to call concurrently with
+// UserMessage(). The message removed by reset.Disable() in unit tests.
+func AddMessage(locale string, code Code, text string) error {
+	t, err := template.New(code.String()).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return NewBug(err)
+	}
+
+	locale = normalizeLocale(locale)
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+	if catalog[locale] == nil {
+		catalog[locale] = map[Code]*template.Template{}
+	}
+	old, exist := catalog[locale][code]
+	catalog[locale][code] = t
+
+	reset.Add(func() {
+		catalogLock.Lock()
+		defer catalogLock.Unlock()
+		if exist {
+			catalog[locale][code] = old
+		} else {
+			delete(catalog[locale], code)
+		}
+	})
+	return nil
+}
+
+// LoadMessages loads catalog files matches glob pattern from fsys, normally
+// an embed.FS. Each file is a json object named by locale, such as
+// "messages/zh-CN.json", keys are registered code names, or code in hex
+// such as "0x04000017", values are message templates, see AddMessage():
+//
+//  {
+//    "billing.CardDeclined": "Card ending {{.last4}} declined",
+//    "GeneralByBug": "Sorry, please try again later"
+//  }
+func LoadMessages(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return NewBug(err)
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return NewRuntime(err)
+		}
+
+		var messages map[string]string
+		if err = json.Unmarshal(data, &messages); err != nil {
+			return Wrapf(ByBug, err, "[errors] bad message file %s: %s", file, err)
+		}
+
+		locale := strings.TrimSuffix(path.Base(file), path.Ext(file))
+		for key, text := range messages {
+			code, err := parseCodeKey(key)
+			if err != nil {
+				return Wrapf(ByBug, err, "[errors] bad message file %s: %s", file, err)
+			}
+			if err = AddMessage(locale, code, text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UserMessage returns message of err for end-user in locale of ctx, looks up
+// the catalog by code of err, then general code of its CausedBy, such as
+// GeneralByInput. Locale falls back from "zh-Hant-TW" to "zh-Hant", "zh" and
+// then the default locale.
+//
+// If not defined in catalog, returns PublicMessage(err). Returns "" if err
+// is nil.
+func UserMessage(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	code := GetCode(err)
+	locales := localeChain(LocaleFrom(ctx))
+	for _, c := range []Code{code, Code(code.Caused())} {
+		for _, locale := range locales {
+			if t := lookupMessage(locale, c); t != nil {
+				if msg, ok := executeMessage(t, err); ok {
+					return msg
+				}
+			}
+		}
+	}
+
+	return PublicMessage(err)
+}
+
+func lookupMessage(locale string, code Code) *template.Template {
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+
+	return catalog[locale][code]
+}
+
+// executeMessage executes message template with attributes of err, returns
+// false if failed.
+func executeMessage(t *template.Template, err error) (string, bool) {
+	data := map[string]interface{}{}
+	for _, attr := range GetAttrs(err) {
+		data[attr.Key] = attr.Value
+	}
+
+	buf := bytes.Buffer{}
+	if e := t.Execute(&buf, data); e != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+func parseCodeKey(key string) (Code, error) {
+	if info, ok := LookupCodeName(key); ok {
+		return info.Code, nil
+	}
+
+	if strings.HasPrefix(key, "0x") {
+		v, err := strconv.ParseUint(key[2:], 16, 32)
+		if err == nil {
+			return Code(v), nil
+		}
+	}
+	return NotError, Bugf("unknown code %q", key)
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "_", "-", -1))
+}
+
+// localeChain returns locales to look up, such as "zh-hant-tw", "zh-hant",
+// "zh", and then the default locale.
+func localeChain(locale string) []string {
+	var r []string
+	for locale = normalizeLocale(locale); locale != ""; {
+		r = append(r, locale)
+		idx := strings.LastIndex(locale, "-")
+		if idx < 0 {
+			break
+		}
+		locale = locale[:idx]
+	}
+	return append(r, normalizeLocale(defaultLocale.Load().(string)))
+}
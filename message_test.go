@@ -0,0 +1,79 @@
+package errors_test
+
+import (
+	"context"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("UserMessage", func() {
+	declined := errors.MustRegisterCode(errors.NewCode(errors.ByInput, 0x3001), "test.msg.Declined", "")
+	outage := errors.NewCode(errors.ByExternal, 0x3002)
+
+	BeforeEach(func() {
+		Ω(errors.LoadMessages(fstest.MapFS{
+			"messages/en.json": {Data: []byte(`{
+				"test.msg.Declined": "Card ending {{.last4}} declined",
+				"0x03003002": "Payment service unavailable"
+			}`)},
+			"messages/zh.json": {Data: []byte(`{
+				"test.msg.Declined": "尾号{{.last4}}的卡被拒绝"
+			}`)},
+			"messages/zh-Hant.json": {Data: []byte(`{
+				"test.msg.Declined": "尾號{{.last4}}的卡被拒絕"
+			}`)},
+			"messages/readme.txt": {Data: []byte(`not a message file`)},
+		}, "messages/*.json")).Should(Succeed())
+	})
+
+	ctxOf := func(locale string) context.Context {
+		if locale == "" {
+			return context.Background()
+		}
+		return errors.WithLocale(context.Background(), locale)
+	}
+
+	DescribeTable("Locale fallback", func(locale, exp string) {
+		e := errors.Coded(declined, "card 1234 declined by bank: insufficient funds").With("last4", "1234")
+		Ω(errors.UserMessage(ctxOf(locale), e)).Should(Equal(exp))
+	},
+		Entry("default", "", "Card ending 1234 declined"),
+		Entry("en-US", "en-US", "Card ending 1234 declined"),
+		Entry("zh-CN", "zh_CN", "尾号1234的卡被拒绝"),
+		Entry("zh-Hant-TW", "zh-Hant-TW", "尾號1234的卡被拒絕"),
+		Entry("not exist", "fr", "Card ending 1234 declined"),
+	)
+
+	It("Hex code key", func() {
+		Ω(errors.UserMessage(ctxOf("zh"), errors.Coded(outage, "timeout"))).Should(Equal("Payment service unavailable"))
+	})
+
+	It("General code", func() {
+		code := errors.NewCode(errors.ByRuntime, 0x3003)
+		Ω(errors.AddMessage("en", errors.GeneralByRuntime, "Try again later")).Should(Succeed())
+		Ω(errors.UserMessage(nil, errors.Coded(code, "disk full"))).Should(Equal("Try again later"))
+	})
+
+	DescribeTable("Not in catalog", func(err error, exp string) {
+		Ω(errors.UserMessage(context.Background(), err)).Should(Equal(exp))
+	},
+		Entry("nil", nil, ""),
+		Entry("ByBug", errors.Bug("nil pointer"), errors.Apology),
+		Entry("ByInput", errors.Input("name required"), "name required"),
+	)
+
+	It("Bad template", func() {
+		Ω(errors.AddMessage("en", outage, "{{")).ShouldNot(Succeed())
+	})
+
+	It("Bad file", func() {
+		Ω(errors.LoadMessages(fstest.MapFS{"en.json": {Data: []byte(`{`)}}, "*.json")).ShouldNot(Succeed())
+		Ω(errors.LoadMessages(fstest.MapFS{"en.json": {Data: []byte(`{"not.exist": "foo"}`)}}, "*.json")).ShouldNot(Succeed())
+	})
+
+})
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"fmt"
+	"log"
+)
+
+// CodeNamespace is a sub-range of Code low bits reserved for a component.
+//
+// Code layout with namespace:
+//
+//  | 8 bits CausedBy | 8 bits namespace ID | 16 bits code |
+//
+// Namespace ID 0 is reserved for codes not belongs to any namespace, such as
+// GeneralByBug.
+type CodeNamespace struct {
+	name string
+	id   uint8
+}
+
+var namespaces = map[uint8]*CodeNamespace{}
+
+// Namespace reserves a namespace for a component. Panics if id is zero, or
+// name/id already reserved. Call Namespace() in package initialization code:
+//
+//  var codes = errors.Namespace("billing", 0x12)
+//
+//  var CardDeclined = codes.New(errors.ByInput, 7)
+func Namespace(name string, id uint8) *CodeNamespace {
+	if id == 0 {
+		log.Panicf("[errors] namespace id 0 is reserved")
+	}
+	if name == "" {
+		log.Panicf("[errors] namespace 0x%02x without name", id)
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if ns, ok := namespaces[id]; ok {
+		log.Panicf("[errors] namespace id 0x%02x already reserved by %q", id, ns.name)
+	}
+	for _, ns := range namespaces {
+		if ns.name == name {
+			log.Panicf("[errors] namespace %q already reserved with id 0x%02x", name, ns.id)
+		}
+	}
+
+	ns := &CodeNamespace{name, id}
+	namespaces[id] = ns
+	return ns
+}
+
+// Name of the namespace.
+func (ns *CodeNamespace) Name() string {
+	return ns.name
+}
+
+// ID of the namespace.
+func (ns *CodeNamespace) ID() uint8 {
+	return ns.id
+}
+
+// New create a code inside the namespace, panic if low exceeds 16 bits.
+func (ns *CodeNamespace) New(cause CausedBy, low uint32) Code {
+	if low > 0xffff {
+		log.Panicf("[errors] code 0x%x out of namespace %q range", low, ns.name)
+	}
+	return NewCode(cause, uint32(ns.id)<<16|low)
+}
+
+// Register create a code inside the namespace and register it, code name is
+// prefixed with namespace name, such as "billing.CardDeclined". Panics if the
+// code already registered.
+func (ns *CodeNamespace) Register(cause CausedBy, low uint32, name, description string) Code {
+	return MustRegisterCode(ns.New(cause, low), ns.name+"."+name, description)
+}
+
+func (ns *CodeNamespace) String() string {
+	return fmt.Sprintf("%s(0x%02x)", ns.name, ns.id)
+}
+
+// Namespace returns the namespace that code belongs to, returns nil if code
+// not belongs to any reserved namespace.
+func (code Code) Namespace() *CodeNamespace {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	return namespaces[uint8(code>>16)]
+}
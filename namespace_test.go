@@ -0,0 +1,48 @@
+package errors_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Namespace", func() {
+	var ns = errors.Namespace("test.ns", 0xf1)
+
+	It("New", func() {
+		code := ns.New(errors.ByInput, 7)
+		Ω(code.Caused()).Should(Equal(errors.ByInput))
+		Ω(code).Should(Equal(errors.NewCode(errors.ByInput, 0xf10007)))
+		Ω(code.Namespace()).Should(BeIdenticalTo(ns))
+	})
+
+	It("Out of range", func() {
+		Ω(func() {
+			ns.New(errors.ByInput, 0x10000)
+		}).Should(Panic())
+	})
+
+	It("Register", func() {
+		code := ns.Register(errors.ByExternal, 1, "Foo", "foo")
+		Ω(code.String()).Should(Equal("test.ns.Foo"))
+	})
+
+	It("No namespace", func() {
+		Ω(errors.GeneralByBug.Namespace()).Should(BeNil())
+		Ω(errors.NewCode(errors.ByBug, 0xf20000).Namespace()).Should(BeNil())
+	})
+
+	It("Duplicate", func() {
+		Ω(func() {
+			errors.Namespace("test.ns", 0xf2)
+		}).Should(Panic())
+		Ω(func() {
+			errors.Namespace("test.ns2", 0xf1)
+		}).Should(Panic())
+		Ω(func() {
+			errors.Namespace("test.ns3", 0)
+		}).Should(Panic())
+	})
+
+})
@@ -0,0 +1,57 @@
+package errors
+
+// ExternalMessage is the default public message of ByExternal errors.
+const ExternalMessage = "A service we depend on is temporarily unavailable, please try again later."
+
+// WithPublicMessage returns a copy of the error with the message safe to show
+// to end-user, Error() stays developer oriented. err itself is not changed,
+// same as With():
+//
+//  return errors.NewExternal(err).WithPublicMessage("Payment service unavailable")
+func (err *Error) WithPublicMessage(msg string) *Error {
+	r := err.clone()
+	r.public = msg
+	return r
+}
+
+// PublicMessage returns the message set by WithPublicMessage(), empty if not
+// set.
+func (err *Error) PublicMessage() string {
+	return err.public
+}
+
+// PublicMessage returns the message of err safe to show to end-user, the
+// first public message set by WithPublicMessage() in the wrap chain. If not
+// set, returns default by CausedBy:
+//
+//  ByBug, ByRuntime:   Apology
+//  ByExternal:         ExternalMessage
+//  ByInput, ClientBug: err.Error(), input errors are reported to the user
+//
+// Returns "" if err is nil. Renderers of HTTP/gRPC/CLI should only show public
+// message, or UserMessage() which also looks up localized catalog.
+func PublicMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var r string
+	walk(err, func(e error) bool {
+		if pe, ok := e.(interface{ PublicMessage() string }); ok {
+			r = pe.PublicMessage()
+		}
+		return r != ""
+	})
+	if r != "" {
+		return r
+	}
+
+	switch GetCausedBy(err) {
+	case ByInput, ByClientBug:
+		return err.Error()
+	case ByExternal:
+		return ExternalMessage
+	default:
+		return Apology
+	}
+}
@@ -0,0 +1,65 @@
+package errors_test
+
+import (
+	"encoding/json"
+	syserr "errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("PublicMessage", func() {
+
+	It("WithPublicMessage", func() {
+		e := errors.External("dial tcp 10.0.0.1:443: connection refused").WithPublicMessage("Payment service unavailable")
+		Ω(e.Error()).Should(Equal("dial tcp 10.0.0.1:443: connection refused"))
+		Ω(e.PublicMessage()).Should(Equal("Payment service unavailable"))
+		Ω(errors.PublicMessage(e)).Should(Equal("Payment service unavailable"))
+		Ω(errors.ForLog(e)).Should(ContainSubstring("\nPublic message: Payment service unavailable\n"))
+	})
+
+	It("WithPublicMessage not changes the receiver", func() {
+		sentinel := errors.Input("not found")
+		a, b := sentinel.WithPublicMessage("a"), sentinel.WithPublicMessage("b")
+		Ω(sentinel.PublicMessage()).Should(BeEmpty())
+		Ω(a.PublicMessage()).Should(Equal("a"))
+		Ω(b.PublicMessage()).Should(Equal("b"))
+		Ω(syserr.Is(a, sentinel)).Should(BeTrue())
+	})
+
+	It("Wrap chain", func() {
+		inner := errors.Input("id 3 not found").WithPublicMessage("Not found")
+		Ω(errors.PublicMessage(fmt.Errorf("ctx: %w", inner))).Should(Equal("Not found"))
+		Ω(errors.PublicMessage(errors.Wrap(errors.ByExternal, inner, "bar"))).Should(Equal("Not found"))
+
+		outer := errors.Wrap(errors.ByExternal, inner, "bar").WithPublicMessage("Outer")
+		Ω(errors.PublicMessage(outer)).Should(Equal("Outer"))
+	})
+
+	DescribeTable("Default", func(err error, exp string) {
+		Ω(errors.PublicMessage(err)).Should(Equal(exp))
+	},
+		Entry("nil", nil, ""),
+		Entry("ByBug", errors.Bug("secret"), errors.Apology),
+		Entry("ByRuntime", errors.Runtime("secret"), errors.Apology),
+		Entry("plain error", syserr.New("secret"), errors.Apology),
+		Entry("ByExternal", errors.External("secret"), errors.ExternalMessage),
+		Entry("ByInput", errors.Input("bad name"), "bad name"),
+		Entry("ByClientBug", errors.ClientBug("bad arg"), "bad arg"),
+	)
+
+	It("JSON", func() {
+		data, err := json.Marshal(errors.Bug("secret").WithPublicMessage("oops"))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var r errors.Error
+		Ω(json.Unmarshal(data, &r)).Should(Succeed())
+		Ω(r.Error()).Should(Equal("secret"))
+		Ω(r.PublicMessage()).Should(Equal("oops"))
+	})
+
+})
@@ -0,0 +1,371 @@
+package errors
+
+import (
+	syserr "errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redforks/testing/reset"
+)
+
+// Handle() redacts the error before logging and passing it to Handler, to
+// keep secrets out of logs:
+//
+//  1. Values of sensitive attributes, see AddSensitiveKeys(), are replaced by
+//     Redacted.
+//  2. Error messages, public messages, remote stacks, and string attribute
+//     values are scrubbed by Scrubbers, see SetScrubbers().
+//  3. Values wrapped in Redacted always print as "***".
+//
+// Redaction works on a copy, the original error returned to the caller, such
+// as rendered by httperr, is not changed.
+
+// Redacted wraps a sensitive value, such as password or token, prints as
+// "***" through every formatter and json encoding:
+//
+//  return errors.Input("login failed").With("password", errors.Redacted{pwd})
+type Redacted struct {
+	Value interface{}
+}
+
+const redactedText = "***"
+
+func (r Redacted) String() string {
+	return redactedText
+}
+
+// GoString implements fmt.GoStringer, prints "***" for %#v.
+func (r Redacted) GoString() string {
+	return redactedText
+}
+
+// Format implements fmt.Formatter, prints "***" for all verbs.
+func (r Redacted) Format(s fmt.State, verb rune) {
+	_, _ = s.Write([]byte(redactedText))
+}
+
+// MarshalJSON implements json.Marshaler, encodes as "***".
+func (r Redacted) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redactedText + `"`), nil
+}
+
+// Scrubber removes secrets from s, returns the scrubbed string.
+type Scrubber func(s string) string
+
+var (
+	// ScrubTokens scrubs bearer/basic authorization credentials, JWTs, and
+	// values of common secret names in "key=value" and json "key": "value"
+	// forms, such as "password=xxxx". Values shorter than 4 characters are
+	// kept, they are unlikely secrets, such as "token=}" of a parser error.
+	ScrubTokens Scrubber = scrubTokens
+
+	// ScrubEmails scrubs email addresses.
+	ScrubEmails = RegexpScrubber(
+		regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+		redactedText)
+
+	// ScrubCardNumbers scrubs payment card numbers, 13 to 19 digits optionally
+	// separated by spaces or dashes, starts with an issuer prefix of major
+	// card brands, and passes Luhn check. Not enabled by default, numeric ids,
+	// such as timestamps, can be mistaken as card numbers, enable it by
+	// SetScrubbers() if card numbers may appear in error messages.
+	ScrubCardNumbers Scrubber = scrubCardNumbers
+)
+
+// secretNames are names of secrets scrubbed by ScrubTokens.
+const secretNames = "password|passwd|pwd|secret|token|access_token|refresh_token|api_key|apikey"
+
+var (
+	// scrubbers stores []Scrubber.
+	scrubbers atomic.Value
+
+	// sensitiveKeys stores map[string]bool, replaced as a whole on change.
+	sensitiveKeys atomic.Value
+
+	// sensitiveKeysLock serializes changes of sensitiveKeys.
+	sensitiveKeysLock sync.Mutex
+)
+
+func init() {
+	scrubbers.Store([]Scrubber{ScrubTokens, ScrubEmails})
+	sensitiveKeys.Store(map[string]bool{})
+	AddSensitiveKeys("password", "passwd", "secret", "token", "access_token",
+		"refresh_token", "api_key", "apikey", "authorization", "cookie")
+}
+
+var (
+	authRe   = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtRe    = regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	secretRe = regexp.MustCompile(`(?i)\b(` + secretNames + `)=[^\s&;,:"']{4,}`)
+	jsonRe   = regexp.MustCompile(`(?i)("(?:` + secretNames + `)"\s*:\s*")[^"]{4,}"`)
+	cardRe   = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+
+	// cardIINRe matches issuer prefixes of Visa, Mastercard, American
+	// Express, Discover, JCB, Diners Club and UnionPay.
+	cardIINRe = regexp.MustCompile(`^(?:4|5[1-5]|2[2-7]|3[0478]|35|6[025])`)
+)
+
+// RegexpScrubber returns a Scrubber replaces all matches of re with repl,
+// repl can reference sub matches, see regexp.Regexp.ReplaceAllString().
+func RegexpScrubber(re *regexp.Regexp, repl string) Scrubber {
+	return func(s string) string {
+		return re.ReplaceAllString(s, repl)
+	}
+}
+
+// SetScrubbers replaces scrubbers used by redaction, default are
+// ScrubTokens and ScrubEmails. Call with no argument to disable scrubbing.
+// Safe to call concurrently with Handle().
+func SetScrubbers(s ...Scrubber) {
+	scrubbers.Store(append([]Scrubber(nil), s...))
+}
+
+// AddSensitiveKeys marks attribute keys as sensitive, values of sensitive
+// attributes are replaced by Redacted on redaction. Keys are case
+// insensitive. Keys marked by default: password, passwd, secret, token,
+// access_token, refresh_token, api_key, apikey, authorization and cookie.
+// Safe to call concurrently with Handle(). Keys removed by reset.Disable()
+// in unit tests.
+func AddSensitiveKeys(keys ...string) {
+	sensitiveKeysLock.Lock()
+	defer sensitiveKeysLock.Unlock()
+
+	old := sensitiveKeys.Load().(map[string]bool)
+	m := make(map[string]bool, len(old)+len(keys))
+	for k := range old {
+		m[k] = true
+	}
+	for _, k := range keys {
+		m[strings.ToLower(k)] = true
+	}
+	sensitiveKeys.Store(m)
+
+	reset.Add(func() {
+		sensitiveKeysLock.Lock()
+		defer sensitiveKeysLock.Unlock()
+		sensitiveKeys.Store(old)
+	})
+}
+
+// Scrub applies scrubbers to s.
+func Scrub(s string) string {
+	for _, scrub := range scrubbers.Load().([]Scrubber) {
+		s = scrub(s)
+	}
+	return s
+}
+
+// Redact returns a redacted copy of v, v is not changed, returns v itself if
+// nothing to redact. Handle() redacts the error before calling Handler,
+// normally no need to call Redact() directly.
+//
+// Errors in the wrap chain are copied with messages scrubbed and sensitive
+// attributes redacted, Code of the error preserved. errors.Is() works on
+// the redacted error, but errors.As() only finds copies of *Error,
+// *ValidationError and *List, other error types needs redaction are
+// replaced. Strings are scrubbed, other values returned as is.
+func Redact(v interface{}) interface{} {
+	return redact(v, nil)
+}
+
+// redact redacts v, and sets labels to the copy of the first Error in the
+// wrap chain of v, if labels not nil and the Error has no labels.
+func redact(v interface{}, labels map[string]string) interface{} {
+	if e, ok := v.(error); ok {
+		r, _ := redactError(e, &labels)
+		return r
+	}
+	r, _ := redactValue("", v)
+	return r
+}
+
+// redactValue redacts v of attribute key, returns true if v changed.
+func redactValue(key string, v interface{}) (interface{}, bool) {
+	if _, ok := v.(Redacted); ok {
+		return v, false
+	}
+	if key != "" && sensitiveKeys.Load().(map[string]bool)[strings.ToLower(key)] {
+		return Redacted{v}, true
+	}
+
+	switch val := v.(type) {
+	case error:
+		return redactError(val, nil)
+	case string:
+		r := Scrub(val)
+		return r, r != val
+	default:
+		return v, false
+	}
+}
+
+// redactError returns redacted copy of err, returns err itself and false if
+// nothing to redact. labels, if not nil, are set to the first Error in the
+// wrap chain and set to nil, see redact().
+func redactError(err error, labels *map[string]string) (error, bool) {
+	switch e := err.(type) {
+	case nil:
+		return nil, false
+	case *Error:
+		return e.redact(labels)
+	case *ValidationError:
+		return e.redact(labels)
+	case *List:
+		errs, changed := redactErrors(e.errs, labels)
+		if !changed {
+			return e, false
+		}
+		return &List{errs: errs}, true
+	case interface {
+		error
+		Unwrap() []error
+	}:
+		errs, changed := redactErrors(e.Unwrap(), labels)
+		msg := Scrub(e.Error())
+		if !changed && msg == e.Error() {
+			return e, false
+		}
+		return &redactedJoin{redactedError{msg: msg, code: GetCode(e), orig: e}, errs}, true
+	default:
+		inner, changed := redactError(unwrap(e), labels)
+		msg := Scrub(e.Error())
+		if !changed && msg == e.Error() {
+			return e, false
+		}
+		return &redactedError{msg: msg, code: GetCode(e), inner: inner, orig: e}, true
+	}
+}
+
+func redactErrors(errs []error, labels *map[string]string) ([]error, bool) {
+	r, changed := make([]error, len(errs)), false
+	for i, err := range errs {
+		var c bool
+		r[i], c = redactError(err, labels)
+		changed = changed || c
+	}
+	return r, changed
+}
+
+func (err *Error) redact(labels *map[string]string) (*Error, bool) {
+	var captured map[string]string
+	if labels != nil && *labels != nil {
+		if err.labels == nil {
+			captured = *labels
+		}
+		*labels = nil
+	}
+
+	inner, changed := redactError(err.Err, labels)
+	attrs, attrsChanged := redactAttrs(err.attrs)
+	r := *err
+	r.Err, r.attrs = inner, attrs
+	r.msg, r.public, r.remoteStack = Scrub(err.msg), Scrub(err.public), Scrub(err.remoteStack)
+	if !changed && !attrsChanged && captured == nil && r.msg == err.msg &&
+		r.public == err.public && r.remoteStack == err.remoteStack {
+		return err, false
+	}
+	if captured != nil {
+		r.labels = captured
+	}
+	r.base = err
+	return &r, true
+}
+
+func redactAttrs(attrs []Attr) ([]Attr, bool) {
+	r, changed := make([]Attr, len(attrs)), false
+	for i, attr := range attrs {
+		v, c := redactValue(attr.Key, attr.Value)
+		r[i], changed = Attr{attr.Key, v}, changed || c
+	}
+	if !changed {
+		return attrs, false
+	}
+	return r, true
+}
+
+// redactedError replaces errors can not be copied, such as errors created by
+// fmt.Errorf(). Implements CausedByError to keep code of the original error.
+type redactedError struct {
+	msg   string
+	code  Code
+	inner error
+	orig  error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Code() Code {
+	return e.code
+}
+
+func (e *redactedError) Inner() error {
+	return e.inner
+}
+
+// Unwrap is alias of Inner method, makes errors.As() find redacted copies.
+func (e *redactedError) Unwrap() error {
+	return e.inner
+}
+
+func (e *redactedError) ErrorStack() string {
+	return e.msg
+}
+
+// Is reports whether the original error matches target, makes errors.Is()
+// work on redacted errors.
+func (e *redactedError) Is(target error) bool {
+	return syserr.Is(e.orig, target)
+}
+
+// redactedJoin replaces errors wraps multiple errors, such as errors created
+// by errors.Join() of go 1.20.
+type redactedJoin struct {
+	redactedError
+	errs []error
+}
+
+func (e *redactedJoin) Unwrap() []error {
+	return e.errs
+}
+
+func scrubTokens(s string) string {
+	s = authRe.ReplaceAllString(s, "$1 "+redactedText)
+	s = jwtRe.ReplaceAllString(s, redactedText)
+	s = secretRe.ReplaceAllString(s, "${1}="+redactedText)
+	return jsonRe.ReplaceAllString(s, `${1}`+redactedText+`"`)
+}
+
+func scrubCardNumbers(s string) string {
+	return cardRe.ReplaceAllStringFunc(s, func(m string) string {
+		if cardIINRe.MatchString(m) && luhn(m) {
+			return redactedText
+		}
+		return m
+	})
+}
+
+// luhn returns true if digits in s passes Luhn checksum.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
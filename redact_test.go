@@ -0,0 +1,179 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	syserr "errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/redforks/testing/reset"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Redact", func() {
+
+	It("Redacted prints ***", func() {
+		v := errors.Redacted{"s3cret"}
+		Ω(fmt.Sprint(v)).Should(Equal("***"))
+		Ω(fmt.Sprintf("%v %+v %#v %s %q %d", v, v, v, v, v, v)).Should(Equal("*** *** *** *** *** ***"))
+		Ω(v.String()).Should(Equal("***"))
+
+		data, err := json.Marshal(map[string]interface{}{"pwd": v})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(data)).Should(Equal(`{"pwd":"***"}`))
+
+		e := errors.Input("login failed").With("password", v)
+		Ω(errors.ForLog(e)).Should(ContainSubstring("Attributes: password=***\n"))
+		Ω(errors.ForLog(e)).ShouldNot(ContainSubstring("s3cret"))
+	})
+
+	DescribeTable("Scrub", func(s, exp string) {
+		Ω(errors.Scrub(s)).Should(Equal(exp))
+	},
+		Entry("bearer", "Authorization: Bearer abc.DEF-123_x", "Authorization: Bearer ***"),
+		Entry("jwt", "token eyJhbGciOi.eyJzdWIiOi.SflKxwRJ rejected", "token *** rejected"),
+		Entry("key=value", "GET /api?user=bob&api_key=abc123&x=1", "GET /api?user=bob&api_key=***&x=1"),
+		Entry("json", `body {"user": "bob", "Password": "hunter2"}`, `body {"user": "bob", "Password": "***"}`),
+		Entry("parser error", "unexpected token: } at offset 12", "unexpected token: } at offset 12"),
+		Entry("short value", "bad token=} in expr", "bad token=} in expr"),
+		Entry("email", "user bob.smith+x@mail.example.com not found", "user *** not found"),
+		Entry("card not scrubbed by default", "card 4111 1111 1111 1111 declined", "card 4111 1111 1111 1111 declined"),
+		Entry("nothing", "plain message", "plain message"),
+	)
+
+	DescribeTable("ScrubCardNumbers", func(s, exp string) {
+		Ω(errors.ScrubCardNumbers(s)).Should(Equal(exp))
+	},
+		Entry("card", "card 4111 1111 1111 1111 declined", "card *** declined"),
+		Entry("card dashes", "card 5500-0000-0000-0004 declined", "card *** declined"),
+		Entry("card no separator", "card 378282246310005 declined", "card *** declined"),
+		Entry("not luhn", "order 4111111111111112 failed", "order 4111111111111112 failed"),
+		Entry("not card prefix", "order 1234567890128 failed", "order 1234567890128 failed"),
+	)
+
+	It("ScrubCardNumbers keeps timestamps", func() {
+		start := int64(1760000000000000000)
+		for i := int64(0); i < 1000; i++ {
+			s := strconv.FormatInt(start+i, 10)
+			Ω(errors.ScrubCardNumbers(s)).Should(Equal(s))
+		}
+	})
+
+	It("SetScrubbers", func() {
+		defer errors.SetScrubbers(errors.ScrubTokens, errors.ScrubEmails)
+
+		errors.SetScrubbers()
+		Ω(errors.Scrub("bob@example.com")).Should(Equal("bob@example.com"))
+
+		errors.SetScrubbers(func(s string) string { return s + "!" }, errors.ScrubEmails)
+		Ω(errors.Scrub("bob@example.com")).Should(Equal("***!"))
+	})
+
+	It("Error", func() {
+		inner := fmt.Errorf("connect with token=abc123: %w", io.EOF)
+		e := errors.Wrap(errors.ByExternal, inner, "call failed for bob@example.com").
+			With("Token", "abc").
+			With("user", "bob@example.com").
+			With("id", 3).
+			WithPublicMessage("Sorry bob@example.com")
+
+		r := errors.Redact(e).(*errors.Error)
+		Ω(r).ShouldNot(BeIdenticalTo(e))
+		Ω(r.Error()).Should(Equal("call failed for ***"))
+		Ω(r.PublicMessage()).Should(Equal("Sorry ***"))
+		Ω(fmt.Sprint(r.OwnAttrs())).Should(Equal("[Token=*** user=*** id=3]"))
+		Ω(r.Inner().Error()).Should(Equal("connect with token=***: EOF"))
+		Ω(r.Code()).Should(Equal(e.Code()))
+		Ω(syserr.Is(r, io.EOF)).Should(BeTrue())
+		Ω(r.StackFrames()).Should(Equal(e.StackFrames()))
+
+		// original not changed
+		Ω(e.Error()).Should(Equal("call failed for bob@example.com"))
+		Ω(e.OwnAttrs()[0].Value).Should(Equal("abc"))
+		Ω(e.Inner()).Should(BeIdenticalTo(inner))
+	})
+
+	It("Plain error keeps code", func() {
+		r := errors.Redact(fmt.Errorf("%w: password=hunter2", errors.Runtime("foo")))
+		Ω(r.(error).Error()).Should(Equal("foo: password=***"))
+		Ω(errors.GetCode(r)).Should(Equal(errors.GeneralByRuntime))
+	})
+
+	It("AddSensitiveKeys", func() {
+		errors.AddSensitiveKeys("X-Session")
+		e := errors.Bug("foo").With("x-session", "abc")
+		r := errors.Redact(e).(*errors.Error)
+		Ω(r.OwnAttrs()[0].Value).Should(Equal(errors.Redacted{"abc"}))
+	})
+
+	It("Nothing to redact", func() {
+		e := errors.Wrap(errors.ByBug, io.EOF, "foo").With("id", 3)
+		Ω(errors.Redact(e)).Should(BeIdenticalTo(e))
+		Ω(errors.Redact(3)).Should(Equal(3))
+		Ω(errors.Redact(nil)).Should(BeNil())
+		Ω(errors.Redact("password=hunter2")).Should(Equal("password=***"))
+	})
+
+	It("List and ValidationError", func() {
+		ve := errors.NewValidation().Add("email", "taken", "bob@example.com already used", map[string]interface{}{"password": "x"})
+		l := errors.Join(errors.Input("ok"), ve)
+
+		r := errors.Redact(l).(*errors.List)
+		Ω(r.Errors()[0]).Should(BeIdenticalTo(l.(*errors.List).Errors()[0]))
+		rv := r.Errors()[1].(*errors.ValidationError)
+		Ω(rv.Violations()[0].Message).Should(Equal("*** already used"))
+		Ω(rv.Violations()[0].Params["password"]).Should(Equal(errors.Redacted{"x"}))
+		Ω(ve.Violations()[0].Message).Should(Equal("bob@example.com already used"))
+	})
+
+	It("Concurrent", func() {
+		e := errors.Bug("bad token=abc123").With("password", "hunter2")
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				r := errors.Redact(e).(*errors.Error)
+				Ω(r.StackFrames()).ShouldNot(BeEmpty())
+				Ω(r.Error()).Should(Equal("bad token=***"))
+			}()
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				errors.AddSensitiveKeys("x-session")
+				Ω(e.StackFrames()).ShouldNot(BeEmpty())
+			}()
+		}
+		wg.Wait()
+	})
+
+	Context("Handle", func() {
+		BeforeEach(func() {
+			reset.Enable()
+		})
+
+		AfterEach(func() {
+			errors.SetHandler(nil)
+			reset.Disable()
+		})
+
+		It("Redacts before calling handler", func() {
+			var handled interface{}
+			errors.SetHandler(func(_ context.Context, err interface{}) {
+				handled = err
+			})
+
+			errors.Handle(nil, errors.Bug("bad token=abc123")) // nolint:staticcheck
+			Ω(handled.(error).Error()).Should(Equal("bad token=***"))
+		})
+	})
+
+})
@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CodeInfo describes a registered Code.
+type CodeInfo struct {
+	Code Code
+
+	// Name is a short, unique identifier of the code, such as
+	// "billing.CardDeclined".
+	Name string
+
+	// Description tells what the code means, for support tooling and
+	// documentation.
+	Description string
+}
+
+var (
+	registryLock sync.RWMutex
+	codeInfos    = map[Code]CodeInfo{}
+	codeNames    = map[string]Code{}
+)
+
+// RegisterCode registers code with name and description. Returns ByBug error
+// if code or name already registered.
+//
+// Normally register codes in package initialization code, use
+// MustRegisterCode() to panic on collision.
+func RegisterCode(code Code, name, description string) error {
+	if name == "" {
+		return Bugf("[errors] code %s registered without name", code)
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if info, ok := codeInfos[code]; ok {
+		return Bugf("[errors] code 0x%08x already registered as %q", uint32(code), info.Name)
+	}
+	if exist, ok := codeNames[name]; ok {
+		return Bugf("[errors] code name %q already used by code 0x%08x", name, uint32(exist))
+	}
+
+	codeInfos[code] = CodeInfo{code, name, description}
+	codeNames[name] = code
+	return nil
+}
+
+// MustRegisterCode is panic version of RegisterCode(), returns code, to make
+// declaring code variables easier:
+//
+//  var CardDeclined = errors.MustRegisterCode(
+//    errors.NewCode(errors.ByInput, 1), "billing.CardDeclined", "Card declined by bank")
+func MustRegisterCode(code Code, name, description string) Code {
+	if err := RegisterCode(code, name, description); err != nil {
+		panic(err)
+	}
+	return code
+}
+
+// LookupCode returns registered information of code, returns false if code
+// not registered.
+func LookupCode(code Code) (CodeInfo, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	info, ok := codeInfos[code]
+	return info, ok
+}
+
+// LookupCodeName returns registered information of code by name, returns
+// false if name not registered.
+func LookupCodeName(name string) (CodeInfo, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	code, ok := codeNames[name]
+	if !ok {
+		return CodeInfo{}, false
+	}
+	return codeInfos[code], true
+}
+
+// AllCodes returns all registered codes, sorted by code value.
+func AllCodes() []CodeInfo {
+	registryLock.RLock()
+	r := make([]CodeInfo, 0, len(codeInfos))
+	for _, info := range codeInfos {
+		r = append(r, info)
+	}
+	registryLock.RUnlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Code < r[j].Code
+	})
+	return r
+}
+
+// String returns registered name of the code, if not registered, returns
+// CausedBy and low bits of the code, such as "ByInput(0x000017)".
+func (code Code) String() string {
+	if info, ok := LookupCode(code); ok {
+		return info.Name
+	}
+	return fmt.Sprintf("%s(0x%06x)", code.Caused(), uint32(code)&0x00ffffff)
+}
+
+func init() {
+	MustRegisterCode(NotError, "NotError", "Not an error")
+	MustRegisterCode(GeneralByBug, "GeneralByBug", "General error caused by a bug")
+	MustRegisterCode(GeneralByRuntime, "GeneralByRuntime", "General error caused by runtime")
+	MustRegisterCode(GeneralByExternal, "GeneralByExternal", "General error caused by external service")
+	MustRegisterCode(GeneralByInput, "GeneralByInput", "General error caused by bad input")
+	MustRegisterCode(GeneralByClientBug, "GeneralByClientBug", "General error caused by client bug")
+}
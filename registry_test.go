@@ -0,0 +1,63 @@
+package errors_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Code registry", func() {
+
+	It("Register and lookup", func() {
+		code := errors.NewCode(errors.ByInput, 0x1001)
+		Ω(errors.RegisterCode(code, "test.Registered", "for test")).Should(Succeed())
+
+		info, ok := errors.LookupCode(code)
+		Ω(ok).Should(BeTrue())
+		Ω(info).Should(Equal(errors.CodeInfo{code, "test.Registered", "for test"}))
+		Ω(code.String()).Should(Equal("test.Registered"))
+		Ω(errors.AllCodes()).Should(ContainElement(info))
+
+		info, ok = errors.LookupCodeName("test.Registered")
+		Ω(ok).Should(BeTrue())
+		Ω(info.Code).Should(Equal(code))
+	})
+
+	It("Duplicate code", func() {
+		code := errors.NewCode(errors.ByInput, 0x1002)
+		Ω(errors.RegisterCode(code, "test.Dup1", "")).Should(Succeed())
+		err := errors.RegisterCode(code, "test.Dup2", "")
+		Ω(err).Should(HaveOccurred())
+		Ω(errors.GetCausedBy(err)).Should(Equal(errors.ByBug))
+		Ω(func() {
+			errors.MustRegisterCode(code, "test.Dup3", "")
+		}).Should(Panic())
+	})
+
+	It("Duplicate name", func() {
+		Ω(errors.RegisterCode(errors.NewCode(errors.ByInput, 0x1003), "test.DupName", "")).Should(Succeed())
+		Ω(errors.RegisterCode(errors.NewCode(errors.ByInput, 0x1004), "test.DupName", "")).ShouldNot(Succeed())
+	})
+
+	It("Not registered", func() {
+		code := errors.NewCode(errors.ByExternal, 0x17)
+		_, ok := errors.LookupCode(code)
+		Ω(ok).Should(BeFalse())
+		_, ok = errors.LookupCodeName("test.NotExist")
+		Ω(ok).Should(BeFalse())
+		Ω(code.String()).Should(Equal("ByExternal(0x000017)"))
+	})
+
+	It("General codes registered", func() {
+		Ω(errors.GeneralByInput.String()).Should(Equal("GeneralByInput"))
+	})
+
+	It("AllCodes sorted", func() {
+		all := errors.AllCodes()
+		for i := 1; i < len(all); i++ {
+			Ω(all[i-1].Code < all[i].Code).Should(BeTrue())
+		}
+	})
+
+})
@@ -30,7 +30,7 @@ func NewStackFrame(pc uintptr) (frame StackFrame) {
 	if frame.Func() == nil {
 		return
 	}
-	frame.Package, frame.Name = packageAndName(frame.Func())
+	frame.Package, frame.Name = packageAndName(frame.Func().Name())
 
 	// pc -1 because the program counters we use are usually return addresses,
 	// and we want to show the line that corresponds to the function call
@@ -76,8 +76,19 @@ func (frame *StackFrame) SourceLine() (string, error) {
 	return string(bytes.Trim(lines[frame.LineNumber-1], " \t")), nil
 }
 
-func packageAndName(fn *runtime.Func) (string, string) {
-	name := fn.Name()
+// newStackFrameFromFrame populates a stack frame object from a frame
+// resolved by runtime.CallersFrames(), which accounts for inlined functions.
+func newStackFrameFromFrame(f runtime.Frame) StackFrame {
+	frame := StackFrame{
+		File:           f.File,
+		LineNumber:     f.Line,
+		ProgramCounter: f.PC,
+	}
+	frame.Package, frame.Name = packageAndName(f.Function)
+	return frame
+}
+
+func packageAndName(name string) (string, string) {
 	pkg := ""
 
 	// The name includes the path name to the package, which is unnecessary
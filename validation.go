@@ -0,0 +1,254 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	syserr "errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Violation is a field level validation failure.
+type Violation struct {
+	// Field is the path of the field, such as "address.zip", "items[2].qty".
+	Field string `json:"field"`
+
+	// Code identifies the kind of violation, such as "required", "max".
+	Code string `json:"code"`
+
+	Message string `json:"message"`
+
+	// Params of the violation, such as {"max": 10}, for front-end to
+	// generate localized messages.
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+func (v Violation) String() string {
+	return v.Field + ": " + v.Message
+}
+
+// ValidationError is a ByInput error contains field violations of a form or
+// request. ValidationError wraps an Error holds its code, id, attributes and
+// stack, so errors.As() to *Error works on it:
+//
+//  ve := errors.NewValidation()
+//  if req.Name == "" {
+//    ve.Add("name", "required", "name is required", nil)
+//  }
+//  return ve.Err()
+type ValidationError struct {
+	err  *Error
+	base *ValidationError // the error copied from, by With()
+
+	violations []Violation
+}
+
+var _ CausedByError = &ValidationError{}
+
+const validationMessage = "validation failed"
+
+// NewValidation creates an empty ValidationError.
+func NewValidation() *ValidationError {
+	e := wrap(syserr.New(validationMessage), ByInput)
+	e.Err, e.msg = nil, validationMessage
+	return &ValidationError{err: e}
+}
+
+// Code returns error code, GeneralByInput.
+func (ve *ValidationError) Code() Code {
+	return ve.err.code
+}
+
+// Inner returns the wrapped Error, implements CausedByError interface.
+func (ve *ValidationError) Inner() error {
+	return ve.err
+}
+
+// Unwrap is alias of Inner method, work with errors.As() of go 1.13.
+func (ve *ValidationError) Unwrap() error {
+	return ve.err
+}
+
+// ErrorStack returns a string that contains both the error message and the
+// callstack.
+func (ve *ValidationError) ErrorStack() string {
+	return ve.Error() + "\n" + ve.err.Stack()
+}
+
+// StackFrames returns an array of frames containing information about the
+// stack.
+func (ve *ValidationError) StackFrames() []StackFrame {
+	return ve.err.StackFrames()
+}
+
+// ID returns unique id of the error, see Error.ID().
+func (ve *ValidationError) ID() string {
+	return ve.err.ID()
+}
+
+// With returns a copy of the error with an attribute attached, ve itself is
+// not changed, same as Error.With().
+func (ve *ValidationError) With(key string, value interface{}) *ValidationError {
+	return &ValidationError{
+		err:        ve.err.With(key, value),
+		base:       ve,
+		violations: ve.violations[:len(ve.violations):len(ve.violations)],
+	}
+}
+
+// Is reports whether target is the error ve copied from, by With() or
+// redaction, makes errors.Is() work on the copy.
+func (ve *ValidationError) Is(target error) bool {
+	for e := ve.base; e != nil; e = e.base {
+		if e == target {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnAttrs returns attributes attached to the error.
+func (ve *ValidationError) OwnAttrs() []Attr {
+	return ve.err.attrs
+}
+
+// Attrs returns attributes of the error, see Error.Attrs().
+func (ve *ValidationError) Attrs() []Attr {
+	return GetAttrs(ve)
+}
+
+// Add a violation, returns ve itself to chain calls.
+func (ve *ValidationError) Add(field, code, message string, params map[string]interface{}) *ValidationError {
+	ve.violations = append(ve.violations, Violation{field, code, message, params})
+	return ve
+}
+
+// Violations returns violations added.
+func (ve *ValidationError) Violations() []Violation {
+	return ve.violations
+}
+
+// Err returns nil if no violation added, otherwise returns ve itself.
+func (ve *ValidationError) Err() error {
+	if len(ve.violations) == 0 {
+		return nil
+	}
+	return ve
+}
+
+func (ve *ValidationError) Error() string {
+	buf := bytes.NewBufferString(validationMessage)
+	for i, v := range ve.violations {
+		if i == 0 {
+			buf.WriteString(": ")
+		} else {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(v.String())
+	}
+	return buf.String()
+}
+
+// Format implements fmt.Formatter, same as Error.Format(), %+v and %#v
+// include violations.
+func (ve *ValidationError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			_, _ = io.WriteString(s, ForLog(ve))
+		case s.Flag('#'):
+			_, _ = fmt.Fprintf(s, "&errors.ValidationError{err:%#v, violations:%#v}", ve.err, ve.violations)
+		default:
+			_, _ = io.WriteString(s, ve.Error())
+		}
+	case 's':
+		_, _ = io.WriteString(s, ve.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", ve.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*errors.ValidationError=%s)", verb, ve.Error())
+	}
+}
+
+// MarshalJSON implements json.Marshaler, same schema as Error, with an extra
+// "violations" field:
+//
+//  "violations": [
+//    {"field": "age", "code": "min", "message": "must >= 18", "params": {"min": 18}}
+//  ]
+//
+// The "violations" field is kept if ValidationError is wrapped by other
+// errors.
+func (ve *ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONError(ve))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (ve *ValidationError) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return NewInput(err)
+	}
+
+	*ve = *fromJSONValidation(&je)
+	return nil
+}
+
+// toJSONValidation encodes ve as its inner Error, with its message and
+// violations.
+func toJSONValidation(ve *ValidationError) *jsonError {
+	r := toJSONError(ve.err)
+	r.Message = ve.Error()
+	r.Violations = ve.violations
+	return r
+}
+
+func fromJSONValidation(je *jsonError) *ValidationError {
+	inner := *je
+	inner.Message, inner.Inner, inner.Violations = validationMessage, nil, nil
+	r := &ValidationError{err: fromJSONError(&inner), violations: je.Violations}
+	r.err.Err, r.err.msg = nil, validationMessage
+	return r
+}
+
+func (ve *ValidationError) forLog() string {
+	buf := bytes.NewBufferString(validationMessage)
+	for _, v := range ve.violations {
+		fmt.Fprintf(buf, "\n  %s: %s (%s)", v.Field, v.Message, v.Code)
+		keys := make([]string, 0, len(v.Params))
+		for k := range v.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(buf, " %s=%v", k, v.Params[k])
+		}
+	}
+	return ve.err.forLog(buf.String())
+}
+
+func (ve *ValidationError) redact(labels *map[string]string) (*ValidationError, bool) {
+	err, changed := ve.err.redact(labels)
+	r := &ValidationError{err: err, base: ve, violations: make([]Violation, len(ve.violations))}
+	for i, v := range ve.violations {
+		msg := Scrub(v.Message)
+		changed = changed || msg != v.Message
+		v.Message = msg
+		if v.Params != nil {
+			params := make(map[string]interface{}, len(v.Params))
+			for k, p := range v.Params {
+				var c bool
+				params[k], c = redactValue(k, p)
+				changed = changed || c
+			}
+			v.Params = params
+		}
+		r.violations[i] = v
+	}
+	if !changed {
+		return ve, false
+	}
+	return r, true
+}
@@ -0,0 +1,118 @@
+package errors_test
+
+import (
+	"encoding/json"
+	syserr "errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("ValidationError", func() {
+	var ve *errors.ValidationError
+
+	BeforeEach(func() {
+		ve = errors.NewValidation().
+			Add("name", "required", "name is required", nil).
+			Add("age", "min", "must >= 18", map[string]interface{}{"min": 18})
+	})
+
+	It("Empty", func() {
+		Ω(errors.NewValidation().Err()).Should(BeNil())
+		Ω(errors.NewValidation().Error()).Should(Equal("validation failed"))
+	})
+
+	It("Violations", func() {
+		Ω(ve.Err()).Should(BeIdenticalTo(ve))
+		Ω(ve.Violations()).Should(Equal([]errors.Violation{
+			{Field: "name", Code: "required", Message: "name is required"},
+			{Field: "age", Code: "min", Message: "must >= 18", Params: map[string]interface{}{"min": 18}},
+		}))
+		Ω(ve.Error()).Should(Equal("validation failed: name: name is required; age: must >= 18"))
+	})
+
+	It("ByInput", func() {
+		Ω(errors.GetCausedBy(ve)).Should(Equal(errors.ByInput))
+		Ω(errors.GetCode(fmt.Errorf("ctx: %w", ve))).Should(Equal(errors.GeneralByInput))
+	})
+
+	It("Wraps Error", func() {
+		ve = ve.With("form", "signup")
+		err := fmt.Errorf("ctx: %w", ve.Err())
+
+		var e *errors.Error
+		Ω(syserr.As(err, &e)).Should(BeTrue())
+		Ω(e.Code()).Should(Equal(errors.GeneralByInput))
+		Ω(e.ID()).Should(Equal(ve.ID()))
+		Ω(errors.ErrorID(err)).Should(Equal(ve.ID()))
+		Ω(errors.GetAttrs(err)).Should(Equal([]errors.Attr{{"form", "signup"}}))
+		Ω(ve.Attrs()).Should(Equal([]errors.Attr{{"form", "signup"}}))
+	})
+
+	It("With not changes the receiver", func() {
+		e := ve.With("form", "signup")
+		Ω(ve.Attrs()).Should(BeEmpty())
+		Ω(e.Attrs()).Should(Equal([]errors.Attr{{"form", "signup"}}))
+		Ω(e.Violations()).Should(Equal(ve.Violations()))
+		Ω(syserr.Is(e, ve)).Should(BeTrue())
+
+		e.Add("email", "required", "email is required", nil)
+		Ω(ve.Violations()).Should(HaveLen(2))
+		Ω(e.Violations()).Should(HaveLen(3))
+	})
+
+	It("ForLog", func() {
+		ve = ve.With("form", "signup")
+		s := errors.ForLog(ve)
+		Ω(s).Should(HavePrefix("validation failed\n  name: name is required (required)\n  age: must >= 18 (min) min=18\nCode: GeneralByInput, CausedBy: ByInput\nID: " + ve.ID() + "\nAttributes: form=signup\n"))
+		Ω(s).Should(ContainSubstring("validation_test.go"))
+		Ω(s).ShouldNot(ContainSubstring("Inner error"))
+		Ω(fmt.Sprintf("%+v", ve)).Should(Equal(s))
+		Ω(fmt.Sprintf("%v", ve)).Should(Equal(ve.Error()))
+	})
+
+	It("JSON", func() {
+		data, err := json.Marshal(ve)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var m map[string]interface{}
+		Ω(json.Unmarshal(data, &m)).Should(Succeed())
+		Ω(m).Should(HaveKeyWithValue("message", ve.Error()))
+		Ω(m).Should(HaveKeyWithValue("causedBy", "ByInput"))
+		Ω(m).Should(HaveKeyWithValue("violations", []interface{}{
+			map[string]interface{}{"field": "name", "code": "required", "message": "name is required"},
+			map[string]interface{}{"field": "age", "code": "min", "message": "must >= 18", "params": map[string]interface{}{"min": float64(18)}},
+		}))
+
+		var r errors.ValidationError
+		Ω(json.Unmarshal(data, &r)).Should(Succeed())
+		Ω(r.Error()).Should(Equal(ve.Error()))
+		Ω(r.Violations()).Should(HaveLen(2))
+		Ω(errors.GetCausedBy(&r)).Should(Equal(errors.ByInput))
+	})
+
+	It("JSON wrapped", func() {
+		for _, err := range []error{
+			errors.NewExternal(ve),
+			errors.Wrap(errors.ByExternal, ve, "signup failed"),
+			errors.NewExternal(fmt.Errorf("ctx: %w", ve)),
+		} {
+			data, e := json.Marshal(err)
+			Ω(e).ShouldNot(HaveOccurred())
+			Ω(string(data)).Should(ContainSubstring(`"violations":[{"field":"name"`))
+
+			var r errors.Error
+			Ω(json.Unmarshal(data, &r)).Should(Succeed())
+			var got *errors.ValidationError
+			Ω(syserr.As(&r, &got)).Should(BeTrue())
+			Ω(got.Error()).Should(Equal(ve.Error()))
+			Ω(got.Violations()).Should(HaveLen(2))
+			Ω(got.ID()).Should(Equal(ve.ID()))
+			Ω(errors.GetCausedBy(got)).Should(Equal(errors.ByInput))
+		}
+	})
+
+})
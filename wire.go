@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/binary"
+	syserr "errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Wire encoding preserves Code of an error across process boundaries, such as
+// a backend service returns error to gateway. Two forms provided:
+//
+//  1. Header form: ASCII string can be used as HTTP header or metadata value:
+//     "<8 hex digits code>;<escaped message>[;<escaped stack>]"
+//  2. Binary form: version byte 1, code in 4 bytes big endian, then message
+//     and stack, each prefixed by uvarint length.
+//
+// Decoded error marked as Remote(), keeps the code of the remote error. To
+// remap the remote error, wrap it:
+//
+//  err, _ := errors.DecodeHeader(h)
+//  return errors.Wrap(errors.ByExternal, err, "payment service failed")
+
+const wireVersion = 1
+
+// Remote returns true if the error decoded from wire encoding.
+func (err *Error) Remote() bool {
+	return err.remote
+}
+
+// RemoteStack returns stack of the remote error, empty if stack not encoded,
+// or error is not Remote().
+func (err *Error) RemoteStack() string {
+	return err.remoteStack
+}
+
+// NewRemote creates a Remote() error received from other process, for
+// protocols not covered by wire encoding, such as gRPC status. stack is the
+// remote stack, can be empty.
+func NewRemote(code Code, msg, stack string) *Error {
+	e := wrap(syserr.New(msg), CausedBy(0))
+	e.code, e.remote, e.remoteStack = code, true, stack
+	return e
+}
+
+// EncodeHeader encodes err in header form, encodes stack if withStack is true
+// and err is *Error. Code of plain errors resolved by GetCode(). Returns ""
+// if err is nil.
+func EncodeHeader(err error, withStack bool) string {
+	if err == nil {
+		return ""
+	}
+
+	code, msg, stack := wireFields(err, withStack)
+	s := fmt.Sprintf("%08x;%s", uint32(code), url.QueryEscape(msg))
+	if stack != "" {
+		s += ";" + url.QueryEscape(stack)
+	}
+	return s
+}
+
+// DecodeHeader decodes error encoded by EncodeHeader(), returns ByInput error
+// if s is malformed.
+func DecodeHeader(s string) (*Error, error) {
+	parts := strings.SplitN(s, ";", 3)
+	if len(parts) < 2 || len(parts[0]) != 8 {
+		return nil, Inputf("[errors] malformed error header %q", s)
+	}
+
+	code, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil {
+		return nil, Inputf("[errors] malformed error header %q", s)
+	}
+	msg, err := url.QueryUnescape(parts[1])
+	if err != nil {
+		return nil, Inputf("[errors] malformed error header %q", s)
+	}
+	stack := ""
+	if len(parts) == 3 {
+		if stack, err = url.QueryUnescape(parts[2]); err != nil {
+			return nil, Inputf("[errors] malformed error header %q", s)
+		}
+	}
+
+	r := wrap(syserr.New(msg), CausedBy(0))
+	r.code, r.remote, r.remoteStack = Code(code), true, stack
+	return r, nil
+}
+
+// EncodeBinary encodes err in binary form, encodes stack if withStack is true
+// and err is *Error. Code of plain errors resolved by GetCode(). Returns nil
+// if err is nil.
+func EncodeBinary(err error, withStack bool) []byte {
+	if err == nil {
+		return nil
+	}
+
+	code, msg, stack := wireFields(err, withStack)
+
+	buf := make([]byte, 5, 5+2*binary.MaxVarintLen64+len(msg)+len(stack))
+	buf[0] = wireVersion
+	binary.BigEndian.PutUint32(buf[1:], uint32(code))
+	buf = appendWireString(buf, msg)
+	buf = appendWireString(buf, stack)
+	return buf
+}
+
+// DecodeBinary decodes error encoded by EncodeBinary(), returns ByInput error
+// if data is malformed.
+func DecodeBinary(data []byte) (*Error, error) {
+	if len(data) < 5 || data[0] != wireVersion {
+		return nil, Input("[errors] malformed binary error")
+	}
+
+	code := Code(binary.BigEndian.Uint32(data[1:]))
+	r := bytes.NewReader(data[5:])
+	msg, err := readWireString(r)
+	if err != nil {
+		return nil, err
+	}
+	stack, err := readWireString(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, Input("[errors] malformed binary error")
+	}
+
+	e := wrap(syserr.New(msg), CausedBy(0))
+	e.code, e.remote, e.remoteStack = code, true, stack
+	return e, nil
+}
+
+func wireFields(err error, withStack bool) (code Code, msg, stack string) {
+	code, msg = GetCode(err), err.Error()
+	if e, ok := err.(*Error); ok && withStack {
+		stack = e.Stack()
+	}
+	return
+}
+
+func appendWireString(buf []byte, s string) []byte {
+	var l [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(l[:], uint64(len(s)))
+	return append(append(buf, l[:n]...), s...)
+}
+
+func readWireString(r *bytes.Reader) (string, error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil || l > uint64(r.Len()) {
+		return "", Input("[errors] malformed binary error")
+	}
+
+	buf := make([]byte, l)
+	_, _ = r.Read(buf)
+	return string(buf), nil
+}
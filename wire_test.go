@@ -0,0 +1,113 @@
+package errors_test
+
+import (
+	syserr "errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/redforks/errors"
+)
+
+var _ = Describe("Wire", func() {
+
+	type codec struct {
+		encode func(err error, withStack bool) interface{}
+		decode func(v interface{}) (*errors.Error, error)
+	}
+
+	header := codec{
+		func(err error, withStack bool) interface{} {
+			return errors.EncodeHeader(err, withStack)
+		},
+		func(v interface{}) (*errors.Error, error) {
+			return errors.DecodeHeader(v.(string))
+		},
+	}
+
+	binary := codec{
+		func(err error, withStack bool) interface{} {
+			return errors.EncodeBinary(err, withStack)
+		},
+		func(v interface{}) (*errors.Error, error) {
+			return errors.DecodeBinary(v.([]byte))
+		},
+	}
+
+	cases := []TableEntry{
+		Entry("Header", header),
+		Entry("Binary", binary),
+	}
+
+	DescribeTable("Round trip", func(c codec) {
+		src := errors.Wrap(errors.ByInput, syserr.New("foo"), "a;b%c\n")
+		e, err := c.decode(c.encode(src, false))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(e.Error()).Should(Equal("a;b%c\n"))
+		Ω(e.Remote()).Should(BeTrue())
+		Ω(e.RemoteStack()).Should(BeEmpty())
+		Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByInput))
+		Ω(e.Stack()).Should(ContainSubstring("wire_test.go"))
+	}, cases...)
+
+	DescribeTable("With stack", func(c codec) {
+		e, err := c.decode(c.encode(errors.External("foo"), true))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(e.RemoteStack()).Should(ContainSubstring("wire_test.go"))
+		Ω(errors.ForLog(e)).Should(ContainSubstring("Remote stack:\n"))
+		Ω(errors.GetCausedBy(e)).Should(Equal(errors.ByExternal))
+	}, cases...)
+
+	DescribeTable("Plain error", func(c codec) {
+		e, err := c.decode(c.encode(syserr.New("foo"), true))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(e.Error()).Should(Equal("foo"))
+		Ω(e.RemoteStack()).Should(BeEmpty())
+		Ω(errors.GetCode(e)).Should(Equal(errors.GeneralByBug))
+	}, cases...)
+
+	It("nil", func() {
+		Ω(errors.EncodeHeader(nil, true)).Should(BeEmpty())
+		Ω(errors.EncodeBinary(nil, true)).Should(BeNil())
+	})
+
+	It("NewRemote", func() {
+		e := errors.NewRemote(errors.GeneralByInput, "foo", "stack")
+		Ω(e.Error()).Should(Equal("foo"))
+		Ω(e.Remote()).Should(BeTrue())
+		Ω(e.RemoteStack()).Should(Equal("stack"))
+		Ω(e.Code()).Should(Equal(errors.GeneralByInput))
+		Ω(e.Stack()).Should(ContainSubstring("wire_test.go"))
+	})
+
+	It("Remap", func() {
+		e, err := errors.DecodeHeader(errors.EncodeHeader(errors.Input("foo"), false))
+		Ω(err).ShouldNot(HaveOccurred())
+		r := errors.Wrap(errors.ByExternal, e, "bar")
+		Ω(errors.GetCausedBy(r)).Should(Equal(errors.ByExternal))
+		Ω(r.Inner()).Should(Equal(e))
+	})
+
+	DescribeTable("Malformed header", func(s string) {
+		_, err := errors.DecodeHeader(s)
+		Ω(errors.GetCausedBy(err)).Should(Equal(errors.ByInput))
+	},
+		Entry("empty", ""),
+		Entry("no message", "04000000"),
+		Entry("bad code", "0400000x;foo"),
+		Entry("short code", "400000;foo"),
+		Entry("bad escape", "04000000;%zz"),
+	)
+
+	DescribeTable("Malformed binary", func(data []byte) {
+		_, err := errors.DecodeBinary(data)
+		Ω(errors.GetCausedBy(err)).Should(Equal(errors.ByInput))
+	},
+		Entry("empty", []byte{}),
+		Entry("bad version", []byte{2, 4, 0, 0, 0, 0, 0}),
+		Entry("truncated", []byte{1, 4, 0, 0, 0, 3, 'a'}),
+		Entry("trailing", []byte{1, 4, 0, 0, 0, 0, 0, 0}),
+	)
+
+})